@@ -0,0 +1,203 @@
+#!/usr/bin/env bash
+#
+# Termflix Docker Backup Module
+# Full environment backup and restore for `d backup` / `d restore`
+#
+
+# Prevent multiple sourcing
+[[ -n "${_TERMFLIX_DOCKER_BACKUP_LOADED:-}" ]] && return 0
+_TERMFLIX_DOCKER_BACKUP_LOADED=1
+
+# ═══════════════════════════════════════════════════════════════
+# RESTORE
+# ═══════════════════════════════════════════════════════════════
+
+# Untar a volume backup into a (re)created named volume
+# Args: $1 = volume name, $2 = tar path
+_restore_volume() {
+    docker_cmd volume create "$1" > /dev/null
+    docker_cmd run --rm -v "$1":/data -v "$(dirname "$2")":/backup:ro busybox \
+        tar -xf "/backup/$(basename "$2")" -C /data 2>/dev/null
+}
+
+# Restore an environment archive: recreate volumes, pull images, and
+# bring the stack back up, reporting drift from the original
+# Args: $1 = archive, optionally --project <name> for compose -p
+# Usage: d restore backup-2024.tar.zst [--project name]
+env_restore() {
+    local archive="" project=""
+
+    while [[ $# -gt 0 ]]; do
+        case "$1" in
+            --project)
+                [[ $# -ge 2 ]] || { echo -e "${C_ERROR}✗ --project needs a value${C_RESET}" >&2; return 1; }
+                project="$2"; shift 2 ;;
+            *) archive="$1"; shift ;;
+        esac
+    done
+
+    if [[ ! -f "$archive" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d restore <archive> [--project name]${C_RESET}" >&2
+        return 1
+    fi
+
+    local workdir
+    workdir=$(mktemp -d /tmp/termflix_docker_restore.XXXXXX)
+
+    if [[ "$archive" == *.zst ]] && command -v zstd &> /dev/null; then
+        zstd -dq -c "$archive" | tar -xf - -C "$workdir"
+    else
+        tar -xzf "$archive" -C "$workdir"
+    fi
+    if [[ $? -ne 0 ]]; then
+        rm -rf "$workdir"
+        echo -e "${C_ERROR}✗ Could not unpack ${archive}${C_RESET}" >&2
+        return 1
+    fi
+
+    # Recreate volumes from their tars
+    echo -e "${C_INFO}ℹ Restoring volumes...${C_RESET}"
+    local vol_tar vol
+    for vol_tar in "$workdir"/volumes/*.tar; do
+        [[ -f "$vol_tar" ]] || continue
+        vol=$(basename "$vol_tar" .tar)
+        echo -e "  ${C_SUBTLE}${vol}${C_RESET}"
+        _restore_volume "$vol" "$vol_tar"
+    done
+
+    # Pull the recorded images, reporting digest drift
+    echo -e "${C_INFO}ℹ Pulling images...${C_RESET}"
+    local image before after
+    while IFS= read -r image; do
+        [[ -z "$image" ]] && continue
+        before=$(docker_cmd images --no-trunc -q "$image" 2>/dev/null | head -1)
+        docker_cmd pull -q "$image" > /dev/null 2>&1
+        after=$(docker_cmd images --no-trunc -q "$image" 2>/dev/null | head -1)
+        if [[ -n "$before" && "$before" != "$after" ]]; then
+            echo -e "  ${C_WARNING}⚠ ${image} drifted from the backed-up digest${C_RESET}"
+        else
+            echo -e "  ${C_SUBTLE}${image}${C_RESET}"
+        fi
+    done < "$workdir/images.txt"
+
+    # Bring the stack back up from the archived compose file, if any
+    local compose_src
+    compose_src=$(ls "$workdir"/compose/*.y*ml 2>/dev/null | head -1)
+    if [[ -n "$compose_src" ]]; then
+        echo -e "${C_INFO}ℹ Starting stack from $(basename "$compose_src")...${C_RESET}"
+        # Invoke compose directly: compose_cmd would merge its own
+        # auto-discovered -f flags with the archived file
+        local bin
+        bin=$(compose_bin) || {
+            echo -e "${C_ERROR}✗ docker compose not found${C_RESET}" >&2
+            rm -rf "$workdir"
+            return 1
+        }
+        $bin -f "$compose_src" ${project:+-p "$project"} up -d
+    else
+        echo -e "${C_WARNING}⚠ No compose file in the archive — recreate containers with the saved .run commands in configs/${C_RESET}"
+    fi
+
+    rm -rf "$workdir"
+    echo -e "${C_SUCCESS}✓ Restore complete${C_RESET}"
+}
+
+# ═══════════════════════════════════════════════════════════════
+# BACKUP
+# ═══════════════════════════════════════════════════════════════
+
+# Tar one named volume into a file using a throwaway helper container
+# Args: $1 = volume name, $2 = output tar path
+_backup_volume() {
+    docker_cmd run --rm -v "$1":/data:ro -v "$(dirname "$2")":/backup busybox \
+        tar -cf "/backup/$(basename "$2")" -C /data . 2>/dev/null
+}
+
+# Check a name against include/exclude filters
+# Args: $1 = name, $2 = include pattern (empty = all), $3 = exclude pattern
+_filter_match() {
+    local name="$1" include="$2" exclude="$3"
+    if [[ -n "$include" ]] && [[ "$name" != *"$include"* ]]; then
+        return 1
+    fi
+    if [[ -n "$exclude" ]] && [[ "$name" == *"$exclude"* ]]; then
+        return 1
+    fi
+    return 0
+}
+
+# Capture compose files, container run configs, named volumes, and
+# image references into one archive
+# Args: -o <file> (required), --include/--exclude volume name filters
+# Usage: d backup -o backup-2024.tar.zst [--include pat] [--exclude pat]
+env_backup() {
+    local output="" include="" exclude=""
+
+    while [[ $# -gt 0 ]]; do
+        if [[ "$1" =~ ^(-o|--include|--exclude)$ && $# -lt 2 ]]; then
+            echo -e "${C_ERROR}✗ ${1} needs a value${C_RESET}" >&2
+            return 1
+        fi
+        case "$1" in
+            -o)          output="$2"; shift 2 ;;
+            --include)   include="$2"; shift 2 ;;
+            --exclude)   exclude="$2"; shift 2 ;;
+            *) shift ;;
+        esac
+    done
+
+    if [[ -z "$output" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d backup -o <file> [--include pat] [--exclude pat]${C_RESET}" >&2
+        return 1
+    fi
+
+    local workdir
+    workdir=$(mktemp -d /tmp/termflix_docker_backup.XXXXXX)
+    mkdir -p "$workdir/compose" "$workdir/configs" "$workdir/volumes"
+
+    # Compose files and env files from the current directory
+    local f
+    for f in compose.yml compose.yaml docker-compose.yml docker-compose.yaml docker-compose.override.yml .env; do
+        [[ -f "$f" ]] && cp "$f" "$workdir/compose/"
+    done
+
+    # Run configs + reconstructed run commands for every container
+    echo -e "${C_INFO}ℹ Capturing container configs...${C_RESET}"
+    d_refresh_containers
+    local num id name rest
+    while IFS=$'\t' read -r num id name rest; do
+        _filter_match "$name" "$include" "$exclude" || continue
+        docker_cmd inspect "$id" > "$workdir/configs/${name}.json"
+        container_runcmd "$num" > "$workdir/configs/${name}.run" 2>/dev/null
+    done < "$D_CONTAINER_CACHE"
+
+    # Image references for re-pulling at restore time
+    docker_cmd ps -a --format '{{.Image}}' | sort -u > "$workdir/images.txt"
+
+    # Named volumes, tarred via a helper container
+    echo -e "${C_INFO}ℹ Backing up volumes...${C_RESET}"
+    local vol
+    while IFS= read -r vol; do
+        [[ -z "$vol" ]] && continue
+        _filter_match "$vol" "$include" "$exclude" || continue
+        echo -e "  ${C_SUBTLE}${vol}${C_RESET}"
+        _backup_volume "$vol" "$workdir/volumes/${vol}.tar"
+    done < <(docker_cmd volume ls -q)
+
+    # Archive it all (zstd preferred, gzip fallback)
+    echo -e "${C_INFO}ℹ Writing ${output}...${C_RESET}"
+    if [[ "$output" == *.zst ]] && command -v zstd &> /dev/null; then
+        tar -cf - -C "$workdir" . | zstd -q -o "$output"
+    else
+        tar -czf "$output" -C "$workdir" .
+    fi
+    local rc=$?
+
+    rm -rf "$workdir"
+    if [[ $rc -eq 0 ]]; then
+        echo -e "${C_SUCCESS}✓ Backup written to ${output}${C_RESET}"
+    else
+        echo -e "${C_ERROR}✗ Backup failed${C_RESET}" >&2
+    fi
+    return $rc
+}
@@ -0,0 +1,225 @@
+#!/usr/bin/env bash
+#
+# Termflix Docker Build Module
+# `d build` with buildx/multi-platform support
+#
+
+# Prevent multiple sourcing
+[[ -n "${_TERMFLIX_DOCKER_BUILD_LOADED:-}" ]] && return 0
+_TERMFLIX_DOCKER_BUILD_LOADED=1
+
+# ═══════════════════════════════════════════════════════════════
+# BUILDX
+# ═══════════════════════════════════════════════════════════════
+
+# True when the buildx plugin is usable
+_buildx_available() {
+    "$DOCKER_BIN" buildx version &> /dev/null
+}
+
+# Build an image, routing through buildx when the invocation needs it
+# (multi-platform, --push, a named builder, or extra build contexts).
+# Plain single-platform builds stay on the classic path so podman and
+# older daemons keep working.
+# Usage: d build [-t name:tag] [--build-arg K=V] [-f Dockerfile] [context]
+image_build() {
+    local platform="" builder="" push=0 tag="" file=""
+    local flags=() context=""
+    local need_buildx=0
+
+    while [[ $# -gt 0 ]]; do
+        case "$1" in
+            --platform)
+                platform="$2"; shift ;;
+            --platform=*)
+                platform="${1#*=}" ;;
+            --builder)
+                builder="$2"; need_buildx=1; shift ;;
+            --builder=*)
+                builder="${1#*=}"; need_buildx=1 ;;
+            --push)
+                push=1; need_buildx=1 ;;
+            --build-context)
+                flags+=("$1" "$2"); need_buildx=1; shift ;;
+            --build-context=*)
+                flags+=("$1"); need_buildx=1 ;;
+            -t|--tag)
+                tag="$2"; flags+=("$1" "$2"); shift ;;
+            -t=*|--tag=*)
+                tag="${1#*=}"; flags+=("$1") ;;
+            -f|--file)
+                file="$2"; flags+=("$1" "$2"); shift ;;
+            -f=*|--file=*)
+                file="${1#*=}"; flags+=("$1") ;;
+            # Value-taking flags both paths understand
+            --build-arg|--target|--label|--network|--cache-from|--cache-to)
+                flags+=("$1" "$2"); shift ;;
+            -*)
+                flags+=("$1") ;;
+            *)
+                context="$1" ;;
+        esac
+        shift
+    done
+    [[ -z "$context" ]] && context="."
+
+    # Without -f, make sure the context actually has something to build
+    # and pick up Containerfile for the podman crowd
+    if [[ -z "$file" && ! -f "$context/Dockerfile" ]]; then
+        if [[ -f "$context/Containerfile" ]]; then
+            flags+=(-f "$context/Containerfile")
+        else
+            echo -e "${C_ERROR}✗ No Dockerfile in ${context} — pass one with -f${C_RESET}" >&2
+            local found
+            found=$(find "$context" -maxdepth 2 -name 'Dockerfile*' 2>/dev/null | head -3)
+            [[ -n "$found" ]] && echo -e "${C_MUTED}  Did you mean: $(echo "$found" | tr '\n' ' ')${C_RESET}" >&2
+            return 1
+        fi
+    fi
+
+    # Without -t, suggest <dirname>:<git branch> so the image is
+    # findable later instead of dangling
+    if [[ -z "$tag" ]]; then
+        local name branch
+        name=$(basename "$(cd "$context" && pwd)" | tr '[:upper:]' '[:lower:]' | tr -cd 'a-z0-9._-')
+        branch=$(git -C "$context" rev-parse --abbrev-ref HEAD 2>/dev/null \
+            | tr '/' '-' | tr -cd 'a-zA-Z0-9._-')
+        tag="${name}:${branch:-latest}"
+        echo -e "${C_INFO}ℹ No tag given, using ${tag}${C_RESET}"
+        flags+=(-t "$tag")
+    fi
+
+    # More than one platform means a manifest list, which only buildx
+    # can assemble
+    [[ "$platform" == *,* ]] && need_buildx=1
+
+    if [[ $need_buildx -eq 1 ]] && ! _buildx_available; then
+        echo -e "${C_ERROR}✗ This build needs buildx (docker buildx install)${C_RESET}" >&2
+        return 1
+    fi
+
+    # Plain progress keeps the stream readable when piped
+    local progress="auto"
+    [[ -t 1 ]] || progress="plain"
+
+    local cmd=()
+    if [[ $need_buildx -eq 1 ]] || { [[ -n "$platform" ]] && _buildx_available; }; then
+        cmd=(buildx build --progress "$progress")
+        [[ -n "$builder" ]] && cmd+=(--builder "$builder")
+        [[ -n "$platform" ]] && cmd+=(--platform "$platform")
+        if [[ $push -eq 1 ]]; then
+            cmd+=(--push)
+        elif [[ "$platform" != *,* ]]; then
+            # Single-platform buildx results are discarded unless
+            # explicitly loaded into the local image store
+            cmd+=(--load)
+        fi
+    else
+        cmd=(build)
+        [[ -n "$platform" ]] && cmd+=(--platform "$platform")
+    fi
+
+    docker_cmd "${cmd[@]}" "${flags[@]+"${flags[@]}"}" "$context" || return 1
+
+    # Point at the fresh image's number so `d i <cmd> <n>` works
+    # immediately (pushed multi-platform builds never land locally)
+    if [[ $push -eq 0 && "${D_DRY_RUN:-0}" != "1" ]]; then
+        d_refresh_images
+        local built
+        built=$(awk -F'\t' -v t="$tag" '$3 == t { print $1; exit }' "$D_IMAGE_CACHE")
+        if [[ -n "$built" ]]; then
+            echo -e "${C_SUCCESS}✓ Built ${tag} ${C_RESET}${C_MUTED}— [${built}] in d i ls${C_RESET}"
+        fi
+    fi
+}
+
+# ═══════════════════════════════════════════════════════════════
+# BUILD CACHE
+# ═══════════════════════════════════════════════════════════════
+
+# Build cache usage broken down by cache record type, with totals
+# Usage: d builder du
+builder_du() {
+    local raw
+    raw=$(docker_cmd system df -v \
+        --format '{{range .BuildCache}}{{.CacheType}}\t{{.Size}}\t{{.Reclaimable}}\t{{.LastUsedAt}}\n{{end}}' 2>/dev/null)
+
+    if [[ -z "$raw" ]]; then
+        # Older daemons: fall back to the plain buildx table
+        docker_cmd buildx du
+        return $?
+    fi
+
+    printf "${C_SUBTLE}%-18s %8s %12s  %s${C_RESET}\n" \
+        "CACHE TYPE" "RECORDS" "SIZE" "LAST USED"
+    echo "$raw" | awk -F'\t' '
+        $1 != "" {
+            count[$1]++
+            bytes[$1] += size($2)
+            if ($4 > last[$1]) last[$1] = $4
+            total += size($2)
+        }
+        function size(s,  v) {
+            v = s + 0
+            if (s ~ /TB/) return v * 1024 * 1024
+            if (s ~ /GB/) return v * 1024
+            if (s ~ /MB/) return v
+            if (s ~ /kB/) return v / 1024
+            return v / 1048576
+        }
+        END {
+            for (t in count)
+                printf "%-18s %8d %10.1fMB  %s\n", t, count[t], bytes[t], substr(last[t], 1, 19)
+            printf "%-18s %8s %10.1fMB\n", "total", "", total
+        }' \
+        | sort \
+        | while IFS= read -r line; do
+            if [[ "$line" == total* ]]; then
+                echo -e "${C_CONTRAST}${line}${C_RESET}"
+            else
+                echo -e "${C_MUTED}${line}${C_RESET}"
+            fi
+        done
+}
+
+# Prune the build cache, previewing what goes first
+# Args: --keep <size> keeps that much of the most recently used cache
+# Usage: d builder prune --keep 5GB
+builder_prune() {
+    local keep=""
+    while [[ $# -gt 0 ]]; do
+        case "$1" in
+            --keep) keep="$2"; shift ;;
+            --keep=*) keep="${1#*=}" ;;
+            *)
+                echo -e "${C_ERROR}✗ Usage: d builder prune [--keep <size>]${C_RESET}" >&2
+                return 1
+                ;;
+        esac
+        shift
+    done
+
+    # Preview before touching anything
+    local reclaimable
+    reclaimable=$(docker_cmd system df --format '{{.Type}}\t{{.Reclaimable}}' 2>/dev/null \
+        | awk -F'\t' '$1 == "Build Cache" { print $2 }')
+    if [[ -n "$reclaimable" ]]; then
+        if [[ -n "$keep" ]]; then
+            echo -e "${C_INFO}ℹ ${reclaimable%% *} reclaimable, keeping the newest ${keep}${C_RESET}"
+        else
+            echo -e "${C_INFO}ℹ ${reclaimable%% *} reclaimable${C_RESET}"
+        fi
+    fi
+
+    if [[ -t 0 ]]; then
+        local answer
+        read -r -p "Prune build cache? [y/N] " answer
+        [[ "$answer" == "y" || "$answer" == "Y" ]] || return 0
+    fi
+
+    if [[ -n "$keep" ]]; then
+        docker_cmd builder prune -f --keep-storage "$keep"
+    else
+        docker_cmd builder prune -f
+    fi
+}
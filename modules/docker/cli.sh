@@ -0,0 +1,320 @@
+#!/usr/bin/env bash
+#
+# Termflix Docker CLI Module
+# Command registry and global flag handling for the `d`/`dc` dispatchers
+#
+
+# Prevent multiple sourcing
+[[ -n "${_TERMFLIX_DOCKER_CLI_LOADED:-}" ]] && return 0
+_TERMFLIX_DOCKER_CLI_LOADED=1
+
+# ═══════════════════════════════════════════════════════════════
+# GLOBAL FLAGS
+# ═══════════════════════════════════════════════════════════════
+
+# Global option state, settable from anywhere on the command line
+D_DRY_RUN="${D_DRY_RUN:-0}"
+D_CONTEXT="${D_CONTEXT:-}"
+D_COLOR_MODE="${D_COLOR_MODE:-auto}"
+D_HOST="${D_HOST:-}"
+
+# Resolve a --host argument: ssh://... and tcp://... pass through,
+# anything else is looked up as a named host in the config
+# (host.<name>=ssh://user@server)
+# Args: $1 = host argument
+d_resolve_host() {
+    case "$1" in
+        ssh://*|tcp://*|unix://*) echo "$1" ;;
+        *)
+            local named
+            named=$(d_conf_get "host.$1" "")
+            if [[ -n "$named" ]]; then
+                echo "$named"
+            else
+                echo "$1"
+            fi
+            ;;
+    esac
+}
+
+# Extract global flags from anywhere in the argument list.
+# Remaining (non-global) arguments are left in the D_ARGS array.
+# Args: the full command line
+d_parse_global_flags() {
+    D_ARGS=()
+    while [[ $# -gt 0 ]]; do
+        if [[ "$1" =~ ^(--color|--context|--host)$ && $# -lt 2 ]]; then
+            echo -e "${C_ERROR}✗ ${1} needs a value${C_RESET}" >&2
+            return 1
+        fi
+        case "$1" in
+            --porcelain)  D_PORCELAIN=1 ;;
+            --dry-run)    D_DRY_RUN=1 ;;
+            --no-color)   D_COLOR_MODE="never" ;;
+            --color)      D_COLOR_MODE="$2"; shift ;;
+            --color=*)    D_COLOR_MODE="${1#*=}" ;;
+            --context)    D_CONTEXT="$2"; shift ;;
+            --context=*)  D_CONTEXT="${1#*=}" ;;
+            --host)       D_HOST=$(d_resolve_host "$2"); shift ;;
+            --host=*)     D_HOST=$(d_resolve_host "${1#*=}") ;;
+            *)            D_ARGS+=("$1") ;;
+        esac
+        shift
+    done
+}
+
+# Decide whether color output should be used and blank the palette
+# when it shouldn't: --color=never, the NO_COLOR convention, porcelain
+# mode, or auto mode with stdout not a TTY.
+d_apply_color_mode() {
+    local use_color=true
+
+    case "$D_COLOR_MODE" in
+        never)  use_color=false ;;
+        always) use_color=true ;;
+        auto|*)
+            [[ -t 1 ]] || use_color=false
+            [[ -n "${NO_COLOR:-}" ]] && use_color=false
+            ;;
+    esac
+    [[ "$D_PORCELAIN" == "1" ]] && use_color=false
+
+    if [[ "$use_color" == false ]]; then
+        C_GLOW="" C_SUBTLE="" C_MUTED="" C_SURFACE="" C_CHARCOAL=""
+        C_CONTRAST="" C_ERROR="" C_SUCCESS="" C_WARNING="" C_INFO=""
+        C_PURPLE="" C_PINK="" C_ORANGE="" C_GRAY="" C_RESET=""
+    fi
+}
+
+# ═══════════════════════════════════════════════════════════════
+# DEFAULT OPTIONS
+# ═══════════════════════════════════════════════════════════════
+
+# Per-command defaults live in a plain key=value file, e.g.:
+#   logs.tail=200
+#   stop.timeout=30
+D_CONF_FILE="${HOME}/.config/termflix/docker.conf"
+
+# Richer settings can live in a YAML file instead. Two levels of
+# nesting map onto dotted keys:
+#   cd:
+#     shell: zsh        ->  cd.shell
+D_CONF_YAML="${HOME}/.config/termflix/config.yaml"
+
+# Look up a dotted key in the YAML config
+# Args: $1 = dotted key (e.g. cd.shell)
+_d_conf_yaml_get() {
+    [[ -f "$D_CONF_YAML" ]] || return 1
+
+    local section="${1%%.*}" key="${1#*.}"
+    if [[ "$section" == "$key" ]]; then
+        # Flat top-level key
+        awk -v k="${key}:" '$1 == k { $1 = ""; sub(/^ /, ""); print; exit }' "$D_CONF_YAML"
+        return 0
+    fi
+
+    awk -v s="${section}:" -v k="${key}:" '
+        $0 == s { in_s = 1; next }
+        in_s && /^[^ ]/ { in_s = 0 }
+        in_s && $1 == k { $1 = ""; sub(/^ /, ""); print; exit }' "$D_CONF_YAML"
+}
+
+# Read a per-command default, preferring docker.conf, then the YAML
+# config
+# Args: $1 = dotted key (e.g. logs.tail), $2 = fallback value
+# Output: the configured value, or the fallback
+d_conf_get() {
+    local key="$1" fallback="${2:-}"
+    local value
+
+    if [[ -f "$D_CONF_FILE" ]]; then
+        value=$(grep "^${key}[[:space:]]*=" "$D_CONF_FILE" 2>/dev/null \
+            | tail -1 | sed 's/^[^=]*=[[:space:]]*//')
+        if [[ -n "$value" ]]; then
+            echo "$value"
+            return 0
+        fi
+    fi
+
+    value=$(_d_conf_yaml_get "$key" 2>/dev/null)
+    if [[ -n "$value" ]]; then
+        echo "$value"
+        return 0
+    fi
+
+    echo "$fallback"
+}
+
+# Apply config-driven startup defaults (color mode, compose file).
+# Explicit flags and environment variables still win.
+d_load_config_defaults() {
+    if [[ "$D_COLOR_MODE" == "auto" ]]; then
+        D_COLOR_MODE=$(d_conf_get "color.mode" "auto")
+    fi
+    if [[ -z "${COMPOSE_FILE:-}" ]]; then
+        local cf
+        cf=$(d_conf_get "compose.file" "")
+        [[ -n "$cf" ]] && export COMPOSE_FILE="$cf"
+    fi
+}
+
+# Prepend option words from an environment variable (D_OPTS/DC_OPTS)
+# ahead of the real command line, so explicit flags win. Word-splits
+# the variable deliberately.
+# Args: $1 = variable name, rest = original arguments
+# Result: combined arguments in the D_ARGS array
+d_merge_env_opts() {
+    local var="$1"
+    shift
+    # shellcheck disable=SC2086
+    set -- ${!var:-} "$@"
+    D_ARGS=("$@")
+}
+
+# ═══════════════════════════════════════════════════════════════
+# USER ALIASES
+# ═══════════════════════════════════════════════════════════════
+
+# Expand a user-defined alias from the config and run it. Aliases map
+# a name onto d command words and may chain with &&:
+#   alias.nuke=c stop 1-3 && prune containers
+#   alias.web=compose logs -f web
+# Args: $1 = alias name, rest = extra arguments appended to the last segment
+# Returns: 2 when no such alias exists
+d_run_alias() {
+    local name="$1"
+    shift
+
+    local expansion
+    expansion=$(d_conf_get "alias.${name}" "")
+    [[ -z "$expansion" ]] && return 2
+
+    # Split on && in pure bash (portable across GNU/BSD userlands)
+    local segments=() rest="$expansion"
+    while [[ "$rest" == *"&&"* ]]; do
+        segments+=("${rest%%&&*}")
+        rest="${rest#*&&}"
+    done
+    segments+=("$rest")
+
+    local rc=0 i
+    local last=$(( ${#segments[@]} - 1 ))
+    for i in "${!segments[@]}"; do
+        local segment="${segments[$i]}"
+        [[ -z "${segment// /}" ]] && continue
+        if [[ $i -eq $last ]]; then
+            # Extra command-line arguments land on the final segment
+            # shellcheck disable=SC2086
+            "$SCRIPT_DIR/d" $segment "$@" || rc=$?
+        else
+            # shellcheck disable=SC2086
+            "$SCRIPT_DIR/d" $segment || { rc=$?; break; }
+        fi
+    done
+    return $rc
+}
+
+# ═══════════════════════════════════════════════════════════════
+# COMMAND REGISTRY
+# ═══════════════════════════════════════════════════════════════
+
+# Parallel arrays (Bash 3.2 compatible — no associative arrays)
+# holding command metadata for help output and shell completion
+D_CMD_NAMES=()
+D_CMD_USAGE=()
+D_CMD_SUMMARY=()
+
+# Register a command with the dispatcher
+# Args: $1 = command name, $2 = usage line, $3 = one-line summary
+d_register_cmd() {
+    D_CMD_NAMES+=("$1")
+    D_CMD_USAGE+=("$2")
+    D_CMD_SUMMARY+=("$3")
+}
+
+# Print help for one registered command, or the full listing
+# Args: $1 = optional command name
+d_cmd_help() {
+    local target="${1:-}"
+    local i
+
+    if [[ -n "$target" ]]; then
+        for i in "${!D_CMD_NAMES[@]}"; do
+            if [[ "${D_CMD_NAMES[$i]}" == "$target" ]]; then
+                echo -e "${C_CONTRAST}${D_CMD_USAGE[$i]}${C_RESET}"
+                echo "  ${D_CMD_SUMMARY[$i]}"
+                return 0
+            fi
+        done
+        echo -e "${C_ERROR}✗ Unknown command: ${target}${C_RESET}" >&2
+        return 1
+    fi
+
+    for i in "${!D_CMD_NAMES[@]}"; do
+        printf "  ${C_GLOW}%-26s${C_RESET} %s\n" "${D_CMD_USAGE[$i]}" "${D_CMD_SUMMARY[$i]}"
+    done
+}
+
+# List registered command names (for completion)
+d_cmd_names() {
+    printf '%s\n' "${D_CMD_NAMES[@]}"
+}
+
+# ═══════════════════════════════════════════════════════════════
+# SHELL COMPLETION
+# ═══════════════════════════════════════════════════════════════
+
+# Emit a completion script for the requested shell. Command names come
+# from the registry at generation time; container names and selection
+# numbers are completed dynamically by invoking `d ls --porcelain`.
+# Args: $1 = bash|zsh|fish
+# Usage: eval "$(d completion bash)" / d completion fish > ~/.config/fish/completions/d.fish
+d_completion() {
+    local shell="${1:-}"
+    local names
+    names=$(d_cmd_names | tr '\n' ' ')
+
+    case "$shell" in
+        bash)
+            cat <<EOF
+_d_complete() {
+    local cur="\${COMP_WORDS[COMP_CWORD]}"
+    if [[ \$COMP_CWORD -eq 1 ]]; then
+        COMPREPLY=( \$(compgen -W "${names}" -- "\$cur") )
+        return
+    fi
+    local rows
+    rows=\$(d ls --porcelain 2>/dev/null)
+    COMPREPLY=( \$(compgen -W "\$(echo "\$rows" | cut -f2,4 | tr '\t' '\n')" -- "\$cur") )
+}
+complete -F _d_complete d
+EOF
+            ;;
+        zsh)
+            cat <<EOF
+_d() {
+    if (( CURRENT == 2 )); then
+        compadd ${names}
+        return
+    fi
+    local -a rows
+    rows=(\${(f)"\$(d ls --porcelain 2>/dev/null | cut -f2,4 | tr '\t' '\n')"})
+    compadd -- \$rows
+}
+compdef _d d
+EOF
+            ;;
+        fish)
+            cat <<EOF
+complete -c d -f
+complete -c d -n '__fish_use_subcommand' -a '${names}'
+complete -c d -n 'not __fish_use_subcommand' \\
+    -a '(d ls --porcelain 2>/dev/null | cut -f2,4 | tr \\t \\n)'
+EOF
+            ;;
+        *)
+            echo -e "${C_ERROR}✗ Usage: d completion bash|zsh|fish${C_RESET}" >&2
+            return 1
+            ;;
+    esac
+}
@@ -0,0 +1,1214 @@
+#!/usr/bin/env bash
+#
+# Termflix Docker Compose Module
+# Compose subcommands for the `dc` shortcut
+#
+
+# Prevent multiple sourcing
+[[ -n "${_TERMFLIX_DOCKER_COMPOSE_LOADED:-}" ]] && return 0
+_TERMFLIX_DOCKER_COMPOSE_LOADED=1
+
+# ═══════════════════════════════════════════════════════════════
+# COMPOSE CLI DETECTION
+# ═══════════════════════════════════════════════════════════════
+
+# Return the compose invocation (v2 plugin preferred over v1 binary)
+# Output: command words, space-separated
+compose_bin() {
+    if docker_cmd compose version &> /dev/null; then
+        echo "$DOCKER_BIN compose"
+    elif command -v docker-compose &> /dev/null; then
+        echo "docker-compose"
+    else
+        return 1
+    fi
+}
+
+# Compose files for this invocation: explicit -f flags land here
+# (via dc), otherwise discovery walks upward from the CWD
+D_COMPOSE_FILES=()
+
+# Find compose files by walking up from the current directory; a
+# docker-compose.override.yml next to the main file rides along
+# automatically, matching compose's own behavior
+# Result: D_COMPOSE_FILES filled (untouched when already set via -f)
+compose_discover() {
+    [[ ${#D_COMPOSE_FILES[@]} -gt 0 ]] && return 0
+
+    local dir="$PWD" f
+    while :; do
+        for f in compose.yml compose.yaml docker-compose.yml docker-compose.yaml; do
+            if [[ -f "$dir/$f" ]]; then
+                D_COMPOSE_FILES=("$dir/$f")
+                [[ -f "$dir/docker-compose.override.yml" ]] \
+                    && D_COMPOSE_FILES+=("$dir/docker-compose.override.yml")
+                return 0
+            fi
+        done
+        [[ "$dir" == "/" ]] && break
+        dir=$(dirname "$dir")
+    done
+    return 1
+}
+
+# Env files for this invocation (--env-file, repeatable)
+D_COMPOSE_ENV_FILES=()
+
+# Numbered cache for `dc envs`
+D_ENVFILE_CACHE="${D_CACHE_DIR}/envfiles"
+
+# List the .env files in the compose file's directory with numbers;
+# those numbers work as `dc --env-file <n> up`
+# Usage: dc envs
+compose_envs() {
+    init_docker_cache
+
+    local dir="."
+    local cf
+    cf=$(compose_file 2>/dev/null) && dir=$(dirname "$cf")
+
+    : > "$D_ENVFILE_CACHE"
+    local num=1 f
+    for f in "$dir"/.env "$dir"/.env.*; do
+        [[ -f "$f" ]] || continue
+        printf '%d\t%s\n' "$num" "$f" >> "$D_ENVFILE_CACHE"
+        ((num++))
+    done
+
+    if [[ ! -s "$D_ENVFILE_CACHE" ]]; then
+        echo -e "${C_MUTED}No .env files found in ${dir}${C_RESET}"
+        return 0
+    fi
+
+    local path
+    while IFS=$'\t' read -r num path; do
+        printf "${C_GLOW}[%s]${C_RESET} ${C_CONTRAST}%s${C_RESET} ${C_MUTED}(%s vars)${C_RESET}\n" \
+            "$num" "$path" "$(grep -c '^[A-Za-z_]' "$path" 2>/dev/null)"
+    done < "$D_ENVFILE_CACHE"
+    echo ""
+    echo -e "${C_INFO}ℹ Use one with 'dc --env-file <number> up'${C_RESET}"
+}
+
+# Resolve an --env-file argument: numbers come from the `dc envs`
+# listing, paths pass through
+# Args: $1 = number or path
+d_resolve_env_file() {
+    if [[ "$1" =~ ^[0-9]+$ ]]; then
+        local path
+        path=$(awk -F'\t' -v n="$1" '$1 == n { print $2 }' "$D_ENVFILE_CACHE" 2>/dev/null)
+        if [[ -z "$path" ]]; then
+            echo -e "${C_ERROR}✗ No env file numbered $1 — run 'dc envs' first${C_RESET}" >&2
+            return 1
+        fi
+        echo "$path"
+        return 0
+    fi
+    echo "$1"
+}
+
+# Announce which compose files are in play (once per invocation,
+# and only when it isn't obvious: upward discovery or an override)
+_D_COMPOSE_ANNOUNCED=0
+_compose_announce() {
+    [[ "$_D_COMPOSE_ANNOUNCED" == "1" || "$D_PORCELAIN" == "1" ]] && return 0
+    _D_COMPOSE_ANNOUNCED=1
+    if [[ ${#D_COMPOSE_FILES[@]} -gt 1 || "${D_COMPOSE_FILES[0]}" == */* ]]; then
+        echo -e "${C_INFO}ℹ Using ${D_COMPOSE_FILES[*]}${C_RESET}" >&2
+    fi
+}
+
+# Run a compose command against the discovered files. Mutating
+# commands are printed, not run, under --dry-run.
+compose_cmd() {
+    local bin
+    bin=$(compose_bin)
+    if [[ -z "$bin" ]]; then
+        echo -e "${C_ERROR}✗ docker compose not found${C_RESET}" >&2
+        return 1
+    fi
+
+    local file_args=()
+    if [[ -z "${COMPOSE_FILE:-}" ]] && compose_discover; then
+        local f
+        for f in "${D_COMPOSE_FILES[@]}"; do
+            file_args+=(-f "$f")
+        done
+        _compose_announce
+    fi
+    local ef
+    for ef in "${D_COMPOSE_ENV_FILES[@]+"${D_COMPOSE_ENV_FILES[@]}"}"; do
+        file_args+=(--env-file "$ef")
+    done
+
+    if [[ "${D_DRY_RUN:-0}" == "1" ]]; then
+        case "$1" in
+            ps|config|version|ls|images|top|port|logs|events) ;;
+            *)
+                echo -e "${C_INFO}DRY-RUN:${C_RESET} ${bin} ${file_args[*]+"${file_args[*]} "}$*" >&2
+                return 0
+                ;;
+        esac
+    fi
+    $bin "${file_args[@]+"${file_args[@]}"}" "$@"
+}
+
+# Locate the primary compose file (discovery above, CWD preferred)
+# Output: path to the compose file
+compose_file() {
+    compose_discover || return 1
+    echo "${D_COMPOSE_FILES[0]}"
+}
+
+# ═══════════════════════════════════════════════════════════════
+# HEALTH-GATED UP
+# ═══════════════════════════════════════════════════════════════
+
+# Report the health of one compose service container
+# Args: $1 = container ID
+# Output: healthy | unhealthy | starting | running | <state>
+_service_health() {
+    docker_cmd inspect --format \
+        '{{if .State.Health}}{{.State.Health.Status}}{{else}}{{.State.Status}}{{end}}' "$1" 2>/dev/null
+}
+
+# Poll all project services until each is healthy (or running, for
+# services without a healthcheck)
+# Args: $1 = timeout in seconds
+# Output: failed service names, one per line
+# Returns: 1 when not converged before the timeout
+_wait_for_services() {
+    local timeout="$1"
+    local deadline=$(( $(date +%s) + timeout ))
+
+    while true; do
+        local failed=""
+        local id state
+        while IFS= read -r id; do
+            [[ -z "$id" ]] && continue
+            state=$(_service_health "$id")
+            case "$state" in
+                healthy|running) ;;
+                *) failed+="$(docker_cmd inspect --format '{{.Name}}' "$id" | tr -d /) (${state})"$'\n' ;;
+            esac
+        done < <(compose_cmd ps -q 2>/dev/null)
+
+        if [[ -z "$failed" ]]; then
+            return 0
+        fi
+        if [[ $(date +%s) -ge $deadline ]]; then
+            echo "$failed"
+            return 1
+        fi
+        sleep 2
+    done
+}
+
+# compose up gated on real service readiness, for CI pipelines
+# Args: compose up arguments; recognizes --wait-healthy and --timeout <dur>
+# Usage: dc up -d --wait-healthy [--timeout 180s]
+compose_up_wait_healthy() {
+    local timeout="180"
+    local up_args=()
+
+    while [[ $# -gt 0 ]]; do
+        case "$1" in
+            --wait-healthy) shift ;;
+            --timeout)
+                [[ $# -ge 2 ]] || { echo -e "${C_ERROR}✗ --timeout needs a value${C_RESET}" >&2; return 1; }
+                timeout="${2%s}"; shift 2 ;;
+            --timeout=*) timeout="${1#*=}"; timeout="${timeout%s}"; shift ;;
+            *) up_args+=("$1"); shift ;;
+        esac
+    done
+
+    compose_cmd up "${up_args[@]+"${up_args[@]}"}" || return 1
+
+    echo -e "${C_INFO}ℹ Waiting up to ${timeout}s for services to become healthy...${C_RESET}"
+    local failed
+    if failed=$(_wait_for_services "$timeout"); then
+        echo -e "${C_SUCCESS}✓ All services healthy${C_RESET}"
+        return 0
+    fi
+
+    echo -e "${C_ERROR}✗ Services failed to converge:${C_RESET}" >&2
+    echo "$failed" | sed '/^$/d; s/^/    /' >&2
+    return 1
+}
+
+# ═══════════════════════════════════════════════════════════════
+# ROLLING RESTART
+# ═══════════════════════════════════════════════════════════════
+
+# Wait until one service's containers are healthy/running
+# Args: $1 = service name, $2 = timeout in seconds
+_wait_for_one_service() {
+    local service="$1" timeout="$2"
+    local deadline=$(( $(date +%s) + timeout ))
+
+    while true; do
+        local ok=true
+        local id state
+        while IFS= read -r id; do
+            [[ -z "$id" ]] && continue
+            state=$(_service_health "$id")
+            [[ "$state" == "healthy" || "$state" == "running" ]] || ok=false
+        done < <(compose_cmd ps -q "$service" 2>/dev/null)
+
+        [[ "$ok" == true ]] && return 0
+        [[ $(date +%s) -ge $deadline ]] && return 1
+        sleep 2
+    done
+}
+
+# Restart services one at a time, waiting for health between each,
+# so a stack restart never takes everything down at once
+# Args: optional service names (default: all), --timeout <dur>
+# Usage: dc rolling-restart [services...]
+compose_rolling_restart() {
+    local timeout="120"
+    local services=()
+
+    while [[ $# -gt 0 ]]; do
+        case "$1" in
+            --timeout)
+                [[ $# -ge 2 ]] || { echo -e "${C_ERROR}✗ --timeout needs a value${C_RESET}" >&2; return 1; }
+                timeout="${2%s}"; shift 2 ;;
+            --timeout=*) timeout="${1#*=}"; timeout="${timeout%s}"; shift ;;
+            *) services+=("$1"); shift ;;
+        esac
+    done
+
+    if [[ ${#services[@]} -eq 0 ]]; then
+        local svc
+        while IFS= read -r svc; do
+            [[ -n "$svc" ]] && services+=("$svc")
+        done < <(compose_cmd config --services 2>/dev/null)
+    fi
+
+    if [[ ${#services[@]} -eq 0 ]]; then
+        echo -e "${C_ERROR}✗ No services found — is there a compose file here?${C_RESET}" >&2
+        return 1
+    fi
+
+    local service
+    for service in "${services[@]}"; do
+        echo -e "${C_INFO}ℹ Restarting ${service}...${C_RESET}"
+        if ! compose_cmd restart "$service"; then
+            echo -e "${C_ERROR}✗ Restart of ${service} failed — stopping the rollout${C_RESET}" >&2
+            return 1
+        fi
+        if ! _wait_for_one_service "$service" "$timeout"; then
+            echo -e "${C_ERROR}✗ ${service} did not become healthy within ${timeout}s — stopping the rollout${C_RESET}" >&2
+            return 1
+        fi
+        echo -e "${C_SUCCESS}✓ ${service} healthy${C_RESET}"
+    done
+
+    echo -e "${C_SUCCESS}✓ Rolling restart complete${C_RESET}"
+}
+
+# ═══════════════════════════════════════════════════════════════
+# SAFE DEPLOY
+# ═══════════════════════════════════════════════════════════════
+
+# Record the image each running service is currently on
+# Output (tab-separated): service, image ID, image reference the
+# container was created from (what compose resolves for the service)
+_record_service_digests() {
+    local id
+    while IFS= read -r id; do
+        [[ -z "$id" ]] && continue
+        docker_cmd inspect --format \
+            '{{index .Config.Labels "com.docker.compose.service"}}	{{.Image}}	{{.Config.Image}}' "$id" 2>/dev/null
+    done < <(compose_cmd ps -q 2>/dev/null)
+}
+
+# Deploy with automatic rollback: snapshot digests, up -d --build,
+# wait for health, and on failure pin services back to the previous
+# images — a poor-man's safe deploy for single-host compose setups
+# Args: optionally --timeout <dur> for the health wait
+# Usage: dc deploy [--timeout 180s]
+compose_deploy() {
+    local timeout="180"
+    while [[ $# -gt 0 ]]; do
+        case "$1" in
+            --timeout)
+                [[ $# -ge 2 ]] || { echo -e "${C_ERROR}✗ --timeout needs a value${C_RESET}" >&2; return 1; }
+                timeout="${2%s}"; shift 2 ;;
+            --timeout=*) timeout="${1#*=}"; timeout="${timeout%s}"; shift ;;
+            *) shift ;;
+        esac
+    done
+
+    local snapshot_file="${D_CACHE_DIR}/deploy_digests.$$"
+    init_docker_cache
+    _record_service_digests > "$snapshot_file"
+
+    echo -e "${C_INFO}ℹ Deploying (up -d --build)...${C_RESET}"
+    if ! compose_cmd up -d --build; then
+        rm -f "$snapshot_file"
+        echo -e "${C_ERROR}✗ Deploy failed during up${C_RESET}" >&2
+        return 1
+    fi
+
+    echo -e "${C_INFO}ℹ Waiting up to ${timeout}s for services to become healthy...${C_RESET}"
+    local failed
+    if failed=$(_wait_for_services "$timeout"); then
+        rm -f "$snapshot_file"
+        echo -e "${C_SUCCESS}✓ Deploy healthy${C_RESET}"
+        return 0
+    fi
+
+    echo -e "${C_ERROR}✗ Deploy unhealthy — rolling back:${C_RESET}" >&2
+    echo "$failed" | sed '/^$/d; s/^/    /' >&2
+
+    # Point each service's image reference back at the previous image
+    # ID, so the recreate below picks up the old bits under the tag
+    # compose resolves for that service
+    local service image ref rc=0
+    while IFS=$'\t' read -r service image ref; do
+        [[ -z "$service" || -z "$ref" ]] && continue
+        echo -e "${C_INFO}ℹ Rolling back ${service} → ${image}${C_RESET}"
+        docker_cmd tag "$image" "$ref" 2>/dev/null
+    done < "$snapshot_file"
+
+    # Recreate from the previous images without rebuilding
+    if ! compose_cmd up -d --no-build --force-recreate; then
+        rc=1
+        echo -e "${C_ERROR}✗ Rollback failed — inspect the stack manually${C_RESET}" >&2
+    else
+        echo -e "${C_WARNING}⚠ Rolled back to previous images${C_RESET}"
+    fi
+
+    rm -f "$snapshot_file"
+    return 1
+}
+
+# ═══════════════════════════════════════════════════════════════
+# RUN-TO-COMPOSE CONVERSION
+# ═══════════════════════════════════════════════════════════════
+
+# Translate docker run flags into an equivalent compose service block
+# Args: docker run arguments (optionally prefixed with "docker run")
+# Usage: d convert run -- -d --name web -p 8080:80 -e FOO=bar nginx:1.27
+convert_run_to_compose() {
+    # Tolerate a pasted full command line
+    [[ "$1" == "docker" ]] && shift
+    [[ "$1" == "run" ]] && shift
+
+    local name="" image="" restart="" network="" command=""
+    local ports=() envs=() volumes=() labels=()
+
+    while [[ $# -gt 0 ]]; do
+        if [[ "$1" =~ ^(--name|-p|--publish|-e|--env|-v|--volume|-l|--label|--restart|--network)$ && $# -lt 2 ]]; then
+            echo -e "${C_ERROR}✗ ${1} needs a value${C_RESET}" >&2
+            return 1
+        fi
+        case "$1" in
+            -d|--detach|-i|-t|-it|-ti|--rm|--interactive|--tty) shift ;;
+            --name)       name="$2"; shift 2 ;;
+            --name=*)     name="${1#*=}"; shift ;;
+            -p|--publish) ports+=("$2"); shift 2 ;;
+            -p=*|--publish=*) ports+=("${1#*=}"); shift ;;
+            -e|--env)     envs+=("$2"); shift 2 ;;
+            -e=*|--env=*) envs+=("${1#*=}"); shift ;;
+            -v|--volume)  volumes+=("$2"); shift 2 ;;
+            -v=*|--volume=*) volumes+=("${1#*=}"); shift ;;
+            -l|--label)   labels+=("$2"); shift 2 ;;
+            --restart)    restart="$2"; shift 2 ;;
+            --restart=*)  restart="${1#*=}"; shift ;;
+            --network)    network="$2"; shift 2 ;;
+            --network=*)  network="${1#*=}"; shift ;;
+            -*)
+                echo -e "${C_WARNING}⚠ Ignoring unsupported flag: $1${C_RESET}" >&2
+                # Skip the flag's value when it isn't --flag=value form
+                if [[ "$1" != *=* && -n "${2:-}" && "${2:0:1}" != "-" ]]; then
+                    shift
+                fi
+                shift
+                ;;
+            *)
+                if [[ -z "$image" ]]; then
+                    image="$1"
+                else
+                    command+="${command:+ }$1"
+                fi
+                shift
+                ;;
+        esac
+    done
+
+    if [[ -z "$image" ]]; then
+        echo -e "${C_ERROR}✗ No image found in the run arguments${C_RESET}" >&2
+        return 1
+    fi
+
+    local service="${name:-${image%%:*}}"
+    service="${service##*/}"
+
+    echo "services:"
+    echo "  ${service}:"
+    echo "    image: ${image}"
+    [[ -n "$name" ]] && echo "    container_name: ${name}"
+    [[ -n "$restart" ]] && echo "    restart: ${restart}"
+    if [[ ${#ports[@]} -gt 0 ]]; then
+        echo "    ports:"
+        local p; for p in "${ports[@]}"; do echo "      - \"${p}\""; done
+    fi
+    if [[ ${#envs[@]} -gt 0 ]]; then
+        echo "    environment:"
+        local e; for e in "${envs[@]}"; do echo "      - ${e}"; done
+    fi
+    if [[ ${#volumes[@]} -gt 0 ]]; then
+        echo "    volumes:"
+        local v; for v in "${volumes[@]}"; do echo "      - ${v}"; done
+    fi
+    if [[ ${#labels[@]} -gt 0 ]]; then
+        echo "    labels:"
+        local l; for l in "${labels[@]}"; do echo "      - ${l}"; done
+    fi
+    [[ -n "$network" ]] && {
+        echo "    networks:"
+        echo "      - ${network}"
+    }
+    [[ -n "$command" ]] && echo "    command: ${command}"
+    return 0
+}
+
+# ═══════════════════════════════════════════════════════════════
+# STATIC ANALYSIS (dc lint)
+# ═══════════════════════════════════════════════════════════════
+
+# Lint counters (set by compose_lint, read by _lint_* helpers)
+_LINT_WARNINGS=0
+
+# Report a lint finding
+# Args: $1 = severity (warn|error), $2 = message
+_lint_report() {
+    local severity="$1" msg="$2"
+    if [[ "$severity" == "error" ]]; then
+        echo -e "  ${C_ERROR}✗ ${msg}${C_RESET}"
+    else
+        echo -e "  ${C_WARNING}⚠ ${msg}${C_RESET}"
+    fi
+    ((_LINT_WARNINGS++))
+}
+
+# Check for env var interpolations with no value in the environment or .env
+# Args: $1 = compose file
+_lint_env_vars() {
+    local file="$1"
+    local var
+
+    while IFS= read -r var; do
+        [[ -z "$var" ]] && continue
+        # Defined in the shell environment?
+        [[ -n "${!var:-}" ]] && continue
+        # Defined in .env next to the compose file?
+        if [[ -f "$(dirname "$file")/.env" ]] && grep -q "^${var}=" "$(dirname "$file")/.env"; then
+            continue
+        fi
+        _lint_report warn "Environment variable \$${var} is interpolated but undefined (no shell value, not in .env)"
+    done < <(grep -o '\${[A-Za-z_][A-Za-z0-9_]*' "$file" | sed 's/^\${//' | sort -u)
+}
+
+# Check for duplicate container_name entries
+# Args: $1 = compose file
+_lint_container_names() {
+    local file="$1"
+    local dupes
+    dupes=$(grep -E '^\s+container_name:' "$file" | awk '{ print $2 }' | sort | uniq -d)
+    local name
+    while IFS= read -r name; do
+        [[ -z "$name" ]] && continue
+        _lint_report error "container_name '${name}' is used by more than one service"
+    done <<< "$dupes"
+}
+
+# Check for ports published on all interfaces
+# Args: $1 = compose file
+_lint_ports() {
+    local file="$1"
+    local line
+    while IFS= read -r line; do
+        # "8080:80" with no host IP binds 0.0.0.0
+        local spec
+        spec=$(echo "$line" | sed -E 's/.*-[[:space:]]*"?([^"]*)"?.*/\1/')
+        if [[ "$spec" =~ ^[0-9]+:[0-9]+ ]]; then
+            _lint_report warn "Port '${spec}' publishes on 0.0.0.0 — prefix with 127.0.0.1: if unintentional"
+        fi
+    done < <(grep -E '^\s+-\s+"?[0-9]+:[0-9]+' "$file")
+}
+
+# Check each service for a healthcheck
+# Args: $1 = compose file
+_lint_healthchecks() {
+    local file="$1"
+    local services
+    services=$(awk '/^services:/ { in_svc=1; next }
+                    in_svc && /^[a-zA-Z]/ { in_svc=0 }
+                    in_svc && /^  [a-zA-Z0-9_-]+:/ { gsub(/[: ]/, ""); print }' "$file")
+
+    local svc
+    while IFS= read -r svc; do
+        [[ -z "$svc" ]] && continue
+        # Look for a healthcheck key inside this service block
+        if ! awk -v svc="  ${svc}:" '
+                $0 == svc { in_block=1; next }
+                in_block && /^  [a-zA-Z0-9_-]+:/ { in_block=0 }
+                in_block && /healthcheck:/ { found=1 }
+                END { exit !found }' "$file"; then
+            _lint_report warn "Service '${svc}' has no healthcheck"
+        fi
+    done <<< "$services"
+}
+
+# Check for the deprecated top-level version key
+# Args: $1 = compose file
+_lint_version_key() {
+    local file="$1"
+    if grep -qE '^version:' "$file"; then
+        _lint_report warn "Top-level 'version:' key is deprecated in the compose spec — remove it"
+    fi
+}
+
+# Check for image tags pinned to latest (or untagged)
+# Args: $1 = compose file
+_lint_latest_tags() {
+    local file="$1"
+    local image
+    while IFS= read -r image; do
+        [[ -z "$image" ]] && continue
+        if [[ "$image" == *:latest || "$image" != *:* ]]; then
+            _lint_report warn "Image '${image}' uses the 'latest' tag — pin a version for reproducible deploys"
+        fi
+    done < <(grep -E '^\s+image:' "$file" | awk '{ print $2 }' | tr -d '"')
+}
+
+# Statically check the compose file for common problems
+# Usage: dc lint
+compose_lint() {
+    local file
+    file=$(compose_file)
+    if [[ -z "$file" ]]; then
+        echo -e "${C_ERROR}✗ No compose file found in the current directory${C_RESET}" >&2
+        return 1
+    fi
+
+    echo -e "${C_CONTRAST}Linting ${file}${C_RESET}"
+    echo ""
+
+    _LINT_WARNINGS=0
+    _lint_version_key "$file"
+    _lint_env_vars "$file"
+    _lint_container_names "$file"
+    _lint_ports "$file"
+    _lint_healthchecks "$file"
+    _lint_latest_tags "$file"
+
+    echo ""
+    if [[ $_LINT_WARNINGS -eq 0 ]]; then
+        echo -e "${C_SUCCESS}✓ No problems found${C_RESET}"
+    else
+        echo -e "${C_WARNING}${_LINT_WARNINGS} finding(s)${C_RESET}"
+        return 1
+    fi
+}
+
+# ═══════════════════════════════════════════════════════════════
+# NUMBERED SERVICES
+# ═══════════════════════════════════════════════════════════════
+
+# Service cache (tab-separated): num, service, state
+D_SERVICE_CACHE="${D_CACHE_DIR}/services"
+
+# Refresh the numbered service cache for the current project.
+# Defined-but-stopped services are included so numbers cover the
+# whole compose file.
+compose_refresh_services() {
+    init_docker_cache
+
+    local defined running
+    defined=$(compose_cmd config --services 2>/dev/null)
+    running=$(compose_cmd ps -a --format '{{.Service}}\t{{.State}}' 2>/dev/null)
+
+    if [[ -z "$defined" && -z "$running" ]]; then
+        : > "$D_SERVICE_CACHE"
+        return 1
+    fi
+    [[ -z "$defined" ]] && defined=$(echo "$running" | cut -f1)
+
+    : > "$D_SERVICE_CACHE"
+    local num=1 svc
+    while IFS= read -r svc; do
+        [[ -z "$svc" ]] && continue
+        local state
+        state=$(echo "$running" | awk -F'\t' -v s="$svc" '$1 == s { print $2; exit }')
+        printf '%d\t%s\t%s\n' "$num" "$svc" "${state:-stopped}" >> "$D_SERVICE_CACHE"
+        ((num++))
+    done <<< "$defined"
+}
+
+# Resolve a selection token to a compose service name
+# Args: $1 = token (number or service name)
+d_resolve_service() {
+    local token="$1"
+
+    [[ -f "$D_SERVICE_CACHE" && -s "$D_SERVICE_CACHE" ]] || compose_refresh_services
+
+    if [[ "$token" =~ ^[0-9]+$ ]]; then
+        local svc
+        svc=$(awk -F'\t' -v n="$token" '$1 == n { print $2 }' "$D_SERVICE_CACHE")
+        if [[ -n "$svc" ]]; then
+            echo "$svc"
+            return 0
+        fi
+        echo -e "${C_ERROR}✗ No service numbered ${token} — run 'dc ps' to see the list${C_RESET}" >&2
+        return 1
+    fi
+    echo "$token"
+}
+
+# Numbered service listing, consistent with `d ls`
+# Usage: dc ps
+compose_ps() {
+    if ! compose_refresh_services; then
+        echo -e "${C_MUTED}No compose project here${C_RESET}"
+        return 1
+    fi
+
+    if [[ "$D_PORCELAIN" == "1" ]]; then
+        # v1: service <num> <name> <state>
+        sed 's/^/service\t/' "$D_SERVICE_CACHE"
+        return 0
+    fi
+
+    local num svc state
+    while IFS=$'\t' read -r num svc state; do
+        local color="$C_MUTED"
+        case "$state" in
+            running) color="$C_SUCCESS" ;;
+            exited|stopped) color="$C_MUTED" ;;
+            restarting|paused) color="$C_WARNING" ;;
+        esac
+        printf "${C_GLOW}[%s]${C_RESET} ${C_CONTRAST}%-28s${C_RESET} ${color}%s${C_RESET}\n" \
+            "$num" "$svc" "$state"
+    done < "$D_SERVICE_CACHE"
+}
+
+# Run a compose verb against services selected by number or name
+# Args: $1 = verb (start|stop|restart), rest = selection tokens
+# Usage: dc restart 2 / dc stop 1 3 / dc start 1-2
+compose_service_op() {
+    local verb="$1"
+    shift
+
+    if [[ $# -eq 0 ]]; then
+        # No selection means the whole project, like plain compose
+        compose_cmd "$verb"
+        return $?
+    fi
+
+    local services=() token svc
+    while IFS= read -r token; do
+        svc=$(d_resolve_service "$token") || return 1
+        services+=("$svc")
+    done < <(d_expand_selection "$@")
+
+    compose_cmd "$verb" "${services[@]}"
+}
+
+# Stream logs for services selected by number, flags passed through
+# Args: selection tokens plus docker compose logs flags
+# Usage: dc logs 2 -f
+compose_service_logs() {
+    local flags=() tokens=()
+    local arg
+    for arg in "$@"; do
+        case "$arg" in
+            -*) flags+=("$arg") ;;
+            *)
+                if [[ "$arg" =~ ^[0-9]+(-[0-9]+)?$ ]]; then
+                    tokens+=("$arg")
+                else
+                    flags+=("$arg")
+                fi
+                ;;
+        esac
+    done
+
+    local services=() token svc
+    if [[ ${#tokens[@]} -gt 0 ]]; then
+        while IFS= read -r token; do
+            svc=$(d_resolve_service "$token") || return 1
+            services+=("$svc")
+        done < <(d_expand_selection "${tokens[@]}")
+    fi
+
+    compose_cmd logs "${flags[@]+"${flags[@]}"}" "${services[@]+"${services[@]}"}"
+}
+
+# Scale services, accepting names or listing numbers on either form:
+#   dc scale web=3 worker=5
+#   dc scale 2 4        (service #2 to 4 replicas)
+# Wraps `up -d --scale` and prints the resulting replica status.
+compose_scale() {
+    if [[ $# -eq 0 ]]; then
+        echo -e "${C_ERROR}✗ Usage: dc scale <service>=<count> ... | dc scale <service#> <count>${C_RESET}" >&2
+        return 1
+    fi
+
+    local scale_args=() scaled=()
+    while [[ $# -gt 0 ]]; do
+        local svc count
+        if [[ "$1" == *=* ]]; then
+            svc="${1%%=*}"
+            count="${1#*=}"
+            shift
+        else
+            svc="$1"
+            count="${2:-}"
+            shift 2 2>/dev/null || { shift; count=""; }
+        fi
+
+        if ! [[ "$count" =~ ^[0-9]+$ ]]; then
+            echo -e "${C_ERROR}✗ Replica count missing for '${svc}'${C_RESET}" >&2
+            return 1
+        fi
+        svc=$(d_resolve_service "$svc") || return 1
+        scale_args+=(--scale "${svc}=${count}")
+        scaled+=("$svc")
+    done
+
+    compose_cmd up -d --no-recreate "${scale_args[@]}" || return 1
+
+    echo ""
+    local svc
+    for svc in "${scaled[@]}"; do
+        local replicas
+        replicas=$(compose_cmd ps --format '{{.Service}}' 2>/dev/null | grep -c "^${svc}\$")
+        echo -e "${C_SUCCESS}✓ ${svc}: ${replicas} running${C_RESET}"
+    done
+}
+
+# Process list for compose services by number (all services when no
+# selection)
+# Usage: dc top [service#]
+compose_top() {
+    if [[ $# -eq 0 ]]; then
+        compose_cmd top
+        return $?
+    fi
+
+    local services=() token svc
+    while IFS= read -r token; do
+        svc=$(d_resolve_service "$token") || return 1
+        services+=("$svc")
+    done < <(d_expand_selection "$@")
+
+    compose_cmd top "${services[@]}"
+}
+
+# Published port lookup for one compose service by number
+# Args: $1 = service token, $2 = container port
+# Usage: dc port 2 80
+compose_port() {
+    if [[ -z "${1:-}" || -z "${2:-}" ]]; then
+        echo -e "${C_ERROR}✗ Usage: dc port <service#> <port>${C_RESET}" >&2
+        return 1
+    fi
+
+    local svc
+    svc=$(d_resolve_service "$1") || return 1
+    compose_cmd port "$svc" "$2"
+}
+
+# Build services with live buildkit output, passing --no-cache /
+# --pull through and resolving service numbers; prints the built
+# image sizes afterwards
+# Args: optional flags and service tokens
+# Usage: dc build / dc build 2 --no-cache
+compose_build() {
+    local flags=() services=()
+    local arg
+    for arg in "$@"; do
+        case "$arg" in
+            -*) flags+=("$arg") ;;
+            *)
+                local svc
+                svc=$(d_resolve_service "$arg") || return 1
+                services+=("$svc")
+                ;;
+        esac
+    done
+
+    # Plain progress keeps the stream readable when piped
+    local progress="auto"
+    [[ -t 1 ]] || progress="plain"
+
+    compose_cmd build --progress "$progress" \
+        "${flags[@]+"${flags[@]}"}" "${services[@]+"${services[@]}"}" || return 1
+
+    # Size summary for what was just built
+    local sizes
+    sizes=$(compose_cmd images --format '{{.Service}}\t{{.Repository}}:{{.Tag}}\t{{.Size}}' 2>/dev/null)
+    [[ -z "$sizes" ]] && return 0
+
+    echo ""
+    printf "${C_SUBTLE}%-20s %-40s %s${C_RESET}\n" "SERVICE" "IMAGE" "SIZE"
+    local svc image size
+    while IFS=$'\t' read -r svc image size; do
+        [[ -z "$svc" ]] && continue
+        if [[ ${#services[@]} -gt 0 ]]; then
+            case " ${services[*]} " in
+                *" ${svc} "*) ;;
+                *) continue ;;
+            esac
+        fi
+        printf "${C_CONTRAST}%-20s${C_RESET} %-40s ${C_GLOW}%s${C_RESET}\n" \
+            "$svc" "${image:0:40}" "$size"
+    done <<< "$sizes"
+}
+
+# Validate/render the compose config. Sub-views list just one facet;
+# on failure the offending line is shown in context instead of the
+# bare compose error string.
+# Args: optionally --services | --volumes | --images, rest passed through
+# Usage: dc config / dc config --services
+compose_config() {
+    case "${1:-}" in
+        --services|--volumes|--images)
+            compose_cmd config "$1"
+            return $?
+            ;;
+    esac
+
+    local output
+    if output=$(compose_cmd config "$@" 2>&1); then
+        echo "$output"
+        return 0
+    fi
+
+    echo -e "${C_ERROR}✗ Compose file validation failed:${C_RESET}" >&2
+    echo "$output" | sed 's/^/    /' >&2
+
+    # yaml errors carry a line number — show it in context
+    local lineno
+    lineno=$(echo "$output" | grep -o 'line [0-9]*' | head -1 | awk '{ print $2 }')
+    local cf
+    cf=$(compose_file 2>/dev/null)
+    if [[ -n "$lineno" && -n "$cf" ]]; then
+        echo "" >&2
+        local start=$(( lineno - 3 ))
+        [[ $start -lt 1 ]] && start=1
+        local n=$start line
+        while IFS= read -r line; do
+            if [[ $n -eq $lineno ]]; then
+                echo -e "  ${C_ERROR}${n}: ${line}  ← here${C_RESET}" >&2
+            else
+                echo -e "  ${C_MUTED}${n}: ${line}${C_RESET}" >&2
+            fi
+            ((n++))
+        done < <(sed -n "${start},$(( lineno + 3 ))p" "$cf")
+    fi
+    return 1
+}
+
+# ═══════════════════════════════════════════════════════════════
+# DAEMON-WIDE PROJECTS
+# ═══════════════════════════════════════════════════════════════
+
+# Project cache (tab-separated): num, project, workdir
+D_PROJECT_CACHE="${D_CACHE_DIR}/projects"
+
+# Discover every compose project on the daemon via its label and run
+# project-scoped verbs against listing numbers, from any directory
+# Args: optionally <verb> <number> (down|logs|ps|restart)
+# Usage: d projects / d projects down 2 / d projects logs 1 -f
+d_projects() {
+    init_docker_cache
+
+    local raw
+    raw=$(docker_capture ps -a --filter label=com.docker.compose.project \
+        --format '{{.Label "com.docker.compose.project"}}\t{{.Label "com.docker.compose.project.working_dir"}}\t{{.State}}') || {
+        d_listing_error "containers"
+        return 1
+    }
+
+    : > "$D_PROJECT_CACHE"
+    local num=1 project
+    while IFS= read -r project; do
+        [[ -z "$project" ]] && continue
+        local workdir
+        workdir=$(echo "$raw" | awk -F'\t' -v p="$project" '$1 == p { print $2; exit }')
+        printf '%d\t%s\t%s\n' "$num" "$project" "$workdir" >> "$D_PROJECT_CACHE"
+        ((num++))
+    done < <(echo "$raw" | cut -f1 | sort -u)
+
+    # Verb form: resolve the number and hand off to compose -p
+    if [[ -n "${1:-}" ]]; then
+        local verb="$1"
+        local token="${2:-}"
+        shift 2 2>/dev/null || shift
+
+        if [[ -z "$token" ]]; then
+            echo -e "${C_ERROR}✗ Usage: d projects ${verb} <number>${C_RESET}" >&2
+            return 1
+        fi
+        project=$(awk -F'\t' -v n="$token" '$1 == n { print $2 }' "$D_PROJECT_CACHE")
+        if [[ -z "$project" ]]; then
+            echo -e "${C_ERROR}✗ No project numbered ${token} — run 'd projects' first${C_RESET}" >&2
+            return 1
+        fi
+
+        local bin
+        bin=$(compose_bin) || {
+            echo -e "${C_ERROR}✗ docker compose not found${C_RESET}" >&2
+            return 1
+        }
+        $bin -p "$project" "$verb" "$@"
+        return $?
+    fi
+
+    if [[ ! -s "$D_PROJECT_CACHE" ]]; then
+        echo -e "${C_MUTED}No compose projects running${C_RESET}"
+        return 0
+    fi
+
+    local workdir
+    while IFS=$'\t' read -r num project workdir; do
+        local total running
+        total=$(echo "$raw" | awk -F'\t' -v p="$project" '$1 == p' | grep -c .)
+        running=$(echo "$raw" | awk -F'\t' -v p="$project" '$1 == p && $3 == "running"' | grep -c .)
+
+        local color="$C_SUCCESS"
+        [[ $running -eq 0 ]] && color="$C_MUTED"
+        [[ $running -gt 0 && $running -lt $total ]] && color="$C_WARNING"
+
+        printf "${C_GLOW}[%s]${C_RESET} ${C_CONTRAST}%-24s${C_RESET} ${color}%d/%d running${C_RESET} ${C_MUTED}%s${C_RESET}\n" \
+            "$num" "${project:0:24}" "$running" "$total" "$workdir"
+    done < "$D_PROJECT_CACHE"
+}
+
+# Tear the project down with explicit control over what else gets
+# deleted. Flags pass straight through; on a TTY with no flags, asks
+# about volumes, images, and orphans with a warning for each.
+# Args: docker compose down flags
+# Usage: dc down / dc down --volumes --remove-orphans
+compose_down() {
+    local down_args=("$@")
+
+    if [[ ${#down_args[@]} -eq 0 && -t 0 && -t 1 ]]; then
+        local answer
+        echo -en "${C_WARNING}⚠ Also remove named volumes (data is lost)? [y/N]: ${C_RESET}"
+        read -r answer
+        [[ "$answer" == "y" ]] && down_args+=(--volumes)
+
+        echo -en "${C_WARNING}⚠ Also remove images built by this project? [y/N]: ${C_RESET}"
+        read -r answer
+        [[ "$answer" == "y" ]] && down_args+=(--rmi local)
+
+        echo -en "${C_INFO}Remove orphan containers from removed services? [y/N]: ${C_RESET}"
+        read -r answer
+        [[ "$answer" == "y" ]] && down_args+=(--remove-orphans)
+    fi
+
+    # shellcheck disable=SC2086
+    compose_cmd down ${down_args[@]+"${down_args[@]}"}
+}
+
+# One-off service run with full flag passthrough. The first argument
+# that isn't a flag (or a flag value) is the service — numbers resolve
+# through the service listing — and everything after it is the command.
+# Args: compose run flags, service token, command words
+# Usage: dc run --rm 2 sh / dc run --rm -e DEBUG=1 web ./manage.py migrate
+compose_run() {
+    local pre=() service="" post=()
+
+    while [[ $# -gt 0 ]]; do
+        if [[ -n "$service" ]]; then
+            post+=("$1")
+            shift
+            continue
+        fi
+        case "$1" in
+            -e|--env|--entrypoint|-p|--publish|-u|--user|-w|--workdir|-v|--volume|--name|-l|--label)
+                pre+=("$1" "$2")
+                shift 2
+                ;;
+            -*)
+                pre+=("$1")
+                shift
+                ;;
+            *)
+                service=$(d_resolve_service "$1") || return 1
+                shift
+                ;;
+        esac
+    done
+
+    if [[ -z "$service" ]]; then
+        echo -e "${C_ERROR}✗ Usage: dc run [flags] <service#> [command...]${C_RESET}" >&2
+        return 1
+    fi
+
+    compose_cmd run "${pre[@]+"${pre[@]}"}" "$service" "${post[@]+"${post[@]}"}"
+}
+
+# Pull every service image concurrently, one status line per service,
+# instead of compose's buffered serial output
+# Args: optional service tokens to restrict the pull
+# Usage: dc pull / dc pull 1 3
+compose_pull() {
+    local services=() token svc
+    if [[ $# -gt 0 ]]; then
+        while IFS= read -r token; do
+            svc=$(d_resolve_service "$token") || return 1
+            services+=("$svc")
+        done < <(d_expand_selection "$@")
+    else
+        while IFS= read -r svc; do
+            [[ -z "$svc" ]] && continue
+            services+=("$svc")
+        done < <(compose_cmd config --services 2>/dev/null)
+    fi
+
+    if [[ ${#services[@]} -eq 0 ]]; then
+        echo -e "${C_MUTED}No services to pull${C_RESET}"
+        return 0
+    fi
+
+    init_docker_cache
+    local pids=() logs=()
+    for svc in "${services[@]}"; do
+        local log="${D_CACHE_DIR}/compose_pull.$$.${#pids[@]}"
+        compose_cmd pull -q "$svc" > "$log" 2>&1 &
+        pids+=($!)
+        logs+=("$log")
+        echo -e "${C_INFO}ℹ Pulling ${svc}...${C_RESET}"
+    done
+
+    local i rc=0
+    for i in "${!pids[@]}"; do
+        if wait "${pids[$i]}"; then
+            echo -e "${C_SUCCESS}✓ Pulled ${services[$i]}${C_RESET}"
+        else
+            echo -e "${C_ERROR}✗ Pull failed for ${services[$i]}:${C_RESET}" >&2
+            sed 's/^/    /' "${logs[$i]}" >&2
+            rc=1
+        fi
+        rm -f "${logs[$i]}"
+    done
+    return $rc
+}
+
+# ═══════════════════════════════════════════════════════════════
+# KUBERNETES CONVERSION
+# ═══════════════════════════════════════════════════════════════
+
+# Emit a Deployment (and a Service for published ports) for one
+# compose service, from the canonical JSON rendering
+# Args: $1 = project, $2 = service name, $3 = config JSON, $4 = output dir
+_k8s_manifest() {
+    local project="$1" svc="$2" json="$3" outdir="$4"
+
+    echo "$json" | jq -r --arg s "$svc" --arg p "$project" '
+        .services[$s] |
+        "apiVersion: apps/v1",
+        "kind: Deployment",
+        "metadata:",
+        "  name: \($s)",
+        "  labels:",
+        "    app: \($s)",
+        "    project: \($p)",
+        "spec:",
+        "  replicas: \(.deploy.replicas // 1)",
+        "  selector:",
+        "    matchLabels:",
+        "      app: \($s)",
+        "  template:",
+        "    metadata:",
+        "      labels:",
+        "        app: \($s)",
+        "    spec:",
+        "      containers:",
+        "        - name: \($s)",
+        "          image: \(.image // "\($p)-\($s)")",
+        (if (.environment // {}) | length > 0 then
+            "          env:",
+            (.environment | to_entries[] | "            - name: \(.key)\n              value: \"\(.value)\"")
+        else empty end),
+        (if (.ports // []) | length > 0 then
+            "          ports:",
+            (.ports[] | "            - containerPort: \(.target)")
+        else empty end)
+    ' > "${outdir}/${svc}-deployment.yaml"
+
+    # A Service only makes sense when ports are published
+    local has_ports
+    has_ports=$(echo "$json" | jq -r --arg s "$svc" '.services[$s].ports // [] | length')
+    [[ "$has_ports" == "0" ]] && return 0
+
+    echo "$json" | jq -r --arg s "$svc" '
+        .services[$s] |
+        "apiVersion: v1",
+        "kind: Service",
+        "metadata:",
+        "  name: \($s)",
+        "spec:",
+        "  selector:",
+        "    app: \($s)",
+        "  ports:",
+        (.ports[] | "    - port: \(.published // .target)\n      targetPort: \(.target)")
+    ' > "${outdir}/${svc}-service.yaml"
+}
+
+# Translate the active compose project into Kubernetes manifests:
+# kompose when installed, otherwise a built-in conversion covering
+# image, env, ports, and replicas
+# Args: optionally -o <dir> (default ./k8s)
+# Usage: dc convert k8s [-o dir]
+compose_convert_k8s() {
+    local outdir="k8s"
+    while [[ $# -gt 0 ]]; do
+        case "$1" in
+            -o|--output) outdir="$2"; shift ;;
+            *) ;;
+        esac
+        shift
+    done
+
+    local cf
+    cf=$(compose_file) || {
+        echo -e "${C_ERROR}✗ No compose file found${C_RESET}" >&2
+        return 1
+    }
+    mkdir -p "$outdir"
+
+    if command -v kompose &> /dev/null; then
+        echo -e "${C_INFO}ℹ Converting with kompose...${C_RESET}"
+        kompose convert -f "$cf" -o "$outdir" || return 1
+        echo -e "${C_SUCCESS}✓ Manifests written to ${outdir}/${C_RESET}"
+        return 0
+    fi
+
+    local json
+    json=$(compose_cmd config --format json 2>/dev/null)
+    if [[ -z "$json" ]]; then
+        echo -e "${C_ERROR}✗ Could not render the compose config (and kompose is not installed)${C_RESET}" >&2
+        return 1
+    fi
+
+    local project
+    project=$(echo "$json" | jq -r '.name // "app"')
+
+    local svc count=0
+    while IFS= read -r svc; do
+        [[ -z "$svc" ]] && continue
+        _k8s_manifest "$project" "$svc" "$json" "$outdir"
+        echo -e "${C_SUCCESS}✓ ${svc} → ${outdir}/${svc}-deployment.yaml${C_RESET}"
+        ((count++))
+    done < <(echo "$json" | jq -r '.services | keys[]')
+
+    echo -e "${C_INFO}ℹ ${count} services converted — volumes and networks need manual attention${C_RESET}"
+}
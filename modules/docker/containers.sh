@@ -0,0 +1,1038 @@
+#!/usr/bin/env bash
+#
+# Termflix Docker Containers Module
+# Container subcommands for `d c ...` operating on numbered selections
+#
+
+# Prevent multiple sourcing
+[[ -n "${_TERMFLIX_DOCKER_CONTAINERS_LOADED:-}" ]] && return 0
+_TERMFLIX_DOCKER_CONTAINERS_LOADED=1
+
+# ═══════════════════════════════════════════════════════════════
+# FILE COPY
+# ═══════════════════════════════════════════════════════════════
+
+# Interactively browse a container's filesystem and pick a file
+# Args: $1 = container ID
+# Output: selected path inside the container
+_container_pick_path() {
+    local id="$1"
+    local listing
+
+    # Walk the container filesystem (depth-limited to stay responsive)
+    listing=$(docker_cmd exec "$id" sh -c \
+        'find / -maxdepth 4 -not -path "/proc/*" -not -path "/sys/*" -not -path "/dev/*" 2>/dev/null' )
+
+    if [[ -z "$listing" ]]; then
+        echo -e "${C_ERROR}✗ Could not list files in container (is it running?)${C_RESET}" >&2
+        return 1
+    fi
+
+    if command -v fzf &> /dev/null; then
+        echo "$listing" | fzf --prompt="container path > " --height=60% --reverse
+    else
+        # Fallback: plain prompt with the listing shown first
+        echo "$listing" | head -100 >&2
+        echo -en "${C_INFO}Path inside container: ${C_RESET}" >&2
+        local path
+        read -r path
+        echo "$path"
+    fi
+}
+
+# Interactively pick a destination path on the host
+# Output: selected host path
+_host_pick_path() {
+    if command -v fzf &> /dev/null; then
+        local dir
+        dir=$( (echo "."; find . -maxdepth 3 -type d -not -path '*/.git/*' 2>/dev/null) \
+            | fzf --prompt="host destination > " --height=60% --reverse)
+        echo "$dir"
+    else
+        echo -en "${C_INFO}Destination on host [.]: ${C_RESET}" >&2
+        local path
+        read -r path
+        echo "${path:-.}"
+    fi
+}
+
+# ═══════════════════════════════════════════════════════════════
+# LIFECYCLE
+# ═══════════════════════════════════════════════════════════════
+
+# Interactive fuzzy picker over the container listing (multi-select)
+# Output: selected listing numbers, one per line
+d_pick_containers() {
+    d_refresh_containers
+
+    if ! command -v fzf &> /dev/null; then
+        echo -e "${C_ERROR}✗ No selection given and fzf is not installed — pass numbers from 'd ls'${C_RESET}" >&2
+        return 1
+    fi
+
+    awk -F'\t' '{ printf "%s  %s  %s  %s\n", $1, $3, $4, $5 }' "$D_CONTAINER_CACHE" \
+        | fzf --multi --prompt="containers > " --height=50% --reverse \
+        | awk '{ print $1 }'
+}
+
+# Run a docker lifecycle action against selected containers. With no
+# selection, drops into the fuzzy picker.
+# Args: $1 = docker verb (start|stop|restart|rm), rest = selection tokens
+container_lifecycle() {
+    local action="$1"
+    shift
+
+    local tokens=("$@")
+    if [[ ${#tokens[@]} -eq 0 ]]; then
+        local picked
+        picked=$(d_pick_containers) || return 1
+        [[ -z "$picked" ]] && return 0
+        while IFS= read -r picked_num; do
+            tokens+=("$picked_num")
+        done <<< "$picked"
+    fi
+
+    local token id rc=0
+    while IFS= read -r token; do
+        id=$(d_resolve_container "$token") || { rc=1; continue; }
+        # $action is intentionally unquoted — verbs may carry a flag
+        # (e.g. "rm -f")
+        if docker_cmd $action "$id" > /dev/null; then
+            echo -e "${C_SUCCESS}✓ ${action} $(d_container_name "$id")${C_RESET}"
+        else
+            echo -e "${C_ERROR}✗ ${action} failed for $(d_container_name "$id")${C_RESET}" >&2
+            rc=1
+        fi
+    done < <(d_expand_selection "${tokens[@]}")
+
+    d_refresh_containers
+    return $rc
+}
+
+# Send a signal to numbered containers (defaults to SIGKILL like
+# docker kill itself)
+# Args: selection tokens, optionally --signal/-s <SIG>
+# Usage: d c kill 2 --signal HUP
+container_kill() {
+    local signal=""
+    local tokens=()
+
+    while [[ $# -gt 0 ]]; do
+        case "$1" in
+            --signal|-s) signal="$2"; shift ;;
+            --signal=*)  signal="${1#*=}" ;;
+            *)           tokens+=("$1") ;;
+        esac
+        shift
+    done
+
+    # The verb carries its flag; container_lifecycle word-splits it
+    container_lifecycle "kill${signal:+ --signal ${signal}}" "${tokens[@]+"${tokens[@]}"}"
+}
+
+# ═══════════════════════════════════════════════════════════════
+# LOGS
+# ═══════════════════════════════════════════════════════════════
+
+# Rotating palette for the multiplexer prefixes
+_LOG_COLORS=("$C_GLOW" "$C_SUCCESS" "$C_WARNING" "$C_INFO" "$C_PURPLE" "$C_PINK" "$C_ORANGE")
+
+# Stream one container's logs with a colored name prefix
+# Args: $1 = container ID, $2 = prefix color, rest = docker logs args
+_prefixed_logs() {
+    local id="$1" color="$2"
+    shift 2
+    local name
+    name=$(d_container_name "$id")
+
+    docker_cmd logs "$@" "$id" 2>&1 | while IFS= read -r line; do
+        echo -e "${color}${name} |${C_RESET} ${line}"
+    done
+}
+
+# Show or stream logs from numbered containers. Several selections
+# interleave compose-style with per-container colored prefixes.
+# Args: selection tokens plus -f/--follow, --tail N, --since <when>
+# Usage: d c logs 1 3 5 -f --tail 100 --since 10m
+container_logs() {
+    local follow=false
+    local tail_n since
+    local tokens=()
+
+    tail_n=$(d_conf_get "logs.tail" "all")
+
+    while [[ $# -gt 0 ]]; do
+        if [[ "$1" =~ ^(--tail|--since)$ && $# -lt 2 ]]; then
+            echo -e "${C_ERROR}✗ ${1} needs a value${C_RESET}" >&2
+            return 1
+        fi
+        case "$1" in
+            -f|--follow) follow=true; shift ;;
+            --tail)      tail_n="$2"; shift 2 ;;
+            --tail=*)    tail_n="${1#*=}"; shift ;;
+            --since)     since="$2"; shift 2 ;;
+            --since=*)   since="${1#*=}"; shift ;;
+            *) tokens+=("$1"); shift ;;
+        esac
+    done
+
+    if [[ ${#tokens[@]} -eq 0 ]]; then
+        echo -e "${C_ERROR}✗ Usage: d c logs <numbers...> [-f] [--tail N] [--since WHEN]${C_RESET}" >&2
+        return 1
+    fi
+
+    local log_args=()
+    [[ "$follow" == true ]] && log_args+=(-f)
+    [[ "$tail_n" != "all" ]] && log_args+=(--tail "$tail_n")
+    [[ -n "$since" ]] && log_args+=(--since "$since")
+
+    # Resolve everything up front so a bad token fails before any stream starts
+    local ids=() token id
+    while IFS= read -r token; do
+        id=$(d_resolve_container "$token") || return 1
+        ids+=("$id")
+    done < <(d_expand_selection "${tokens[@]}")
+
+    if [[ ${#ids[@]} -eq 1 ]]; then
+        docker_cmd logs "${log_args[@]+"${log_args[@]}"}" "${ids[0]}"
+        return $?
+    fi
+
+    # Multiplex: one background stream per container, shared stdout
+    local pids=() i=0
+    trap 'kill "${pids[@]}" 2>/dev/null' INT TERM
+    for id in "${ids[@]}"; do
+        _prefixed_logs "$id" "${_LOG_COLORS[$(( i % ${#_LOG_COLORS[@]} ))]}" \
+            "${log_args[@]+"${log_args[@]}"}" &
+        pids+=($!)
+        ((i++))
+    done
+    wait "${pids[@]}" 2>/dev/null
+    trap - INT TERM
+}
+
+# ═══════════════════════════════════════════════════════════════
+# FILESYSTEM DIFF
+# ═══════════════════════════════════════════════════════════════
+
+# Grouped, colorized view of docker diff output
+# Groups changes by top-level directory with per-directory counts,
+# colors the A/C/D states, and reports sizes of added/changed files.
+# --flat skips the grouping and sizes for a quick scan.
+# Args: $1 = container number, optionally --flat
+container_diff() {
+    local flat=false
+    local token=""
+    local arg
+    for arg in "$@"; do
+        case "$arg" in
+            --flat) flat=true ;;
+            *) token="$arg" ;;
+        esac
+    done
+
+    local id
+    id=$(d_resolve_container "$token") || return 1
+    local name
+    name=$(d_container_name "$id")
+
+    local raw
+    raw=$(docker_cmd diff "$id")
+    if [[ -z "$raw" ]]; then
+        echo -e "${C_MUTED}No filesystem changes in ${name}${C_RESET}"
+        return 0
+    fi
+
+    if [[ "$flat" == true ]]; then
+        local state path
+        while read -r state path; do
+            case "$state" in
+                A) echo -e "${C_SUCCESS}A${C_RESET} $path" ;;
+                C) echo -e "${C_WARNING}C${C_RESET} $path" ;;
+                D) echo -e "${C_ERROR}D${C_RESET} $path" ;;
+            esac
+        done <<< "$raw"
+        return 0
+    fi
+
+    echo -e "${C_CONTRAST}Filesystem changes in ${name}${C_RESET}"
+    echo ""
+
+    # Group by top-level directory, keeping A/C/D markers
+    local groups
+    groups=$(echo "$raw" | awk '{ split($2, p, "/"); print "/" p[2] }' | sort -u)
+
+    local dir
+    while IFS= read -r dir; do
+        local entries added changed deleted
+        entries=$(echo "$raw" | awk -v d="$dir" 'index($2, d) == 1 || $2 == d')
+        added=$(echo "$entries" | grep -c '^A ')
+        changed=$(echo "$entries" | grep -c '^C ')
+        deleted=$(echo "$entries" | grep -c '^D ')
+
+        # Total size of added/changed files still present in the container
+        local size
+        size=$(echo "$entries" | awk '$1 != "D" { print $2 }' \
+            | docker_cmd exec -i "$id" xargs -r du -sch 2>/dev/null \
+            | tail -1 | awk '{ print $1 }')
+
+        echo -e "${C_PURPLE}${dir}${C_RESET} ${C_SUBTLE}(${added} added, ${changed} changed, ${deleted} deleted${size:+, ${size}})${C_RESET}"
+
+        local state path
+        while read -r state path; do
+            case "$state" in
+                A) echo -e "  ${C_SUCCESS}A${C_RESET} $path" ;;
+                C) echo -e "  ${C_WARNING}C${C_RESET} $path" ;;
+                D) echo -e "  ${C_ERROR}D${C_RESET} $path" ;;
+            esac
+        done <<< "$entries"
+    done <<< "$groups"
+}
+
+# ═══════════════════════════════════════════════════════════════
+# COMPOSE EXPORT
+# ═══════════════════════════════════════════════════════════════
+
+# Emit a compose service block for one container
+# Args: $1 = container ID
+_container_compose_service() {
+    local id="$1"
+    docker_cmd inspect "$id" | jq -r '
+        .[0] |
+        "  \(.Name | ltrimstr("/")):",
+        "    image: \(.Config.Image)",
+        (if (.HostConfig.RestartPolicy.Name // "no") != "no"
+            then "    restart: \(.HostConfig.RestartPolicy.Name)" else empty end),
+        (if (.NetworkSettings.Ports // {}) | to_entries | map(select(.value != null)) | length > 0 then
+            "    ports:",
+            (.NetworkSettings.Ports | to_entries[] | select(.value != null) |
+                "      - \"\(.value[0].HostPort):\(.key | split("/")[0])\"")
+        else empty end),
+        (if (.Config.Env // []) | length > 0 then
+            "    environment:",
+            (.Config.Env[] | "      - \(.)")
+        else empty end),
+        (if (.Mounts // []) | length > 0 then
+            "    volumes:",
+            (.Mounts[] | "      - \(.Name // .Source):\(.Destination)")
+        else empty end),
+        (if ((.NetworkSettings.Networks // {}) | keys | map(select(. != "bridge")) | length > 0) then
+            "    networks:",
+            ((.NetworkSettings.Networks | keys[] | select(. != "bridge")) | "      - \(.)")
+        else empty end)
+    '
+}
+
+# Reconstruct the docker run command line that would recreate a container
+# Args: $1 = container number
+# Usage: d c runcmd 2
+container_runcmd() {
+    local id
+    id=$(d_resolve_container "$1") || return 1
+
+    docker_cmd inspect "$id" | jq -r '
+        .[0] |
+        [
+            "docker run -d",
+            "--name \(.Name | ltrimstr("/"))",
+            (if (.HostConfig.RestartPolicy.Name // "no") != "no"
+                then "--restart \(.HostConfig.RestartPolicy.Name)" else empty end),
+            ((.NetworkSettings.Ports // {}) | to_entries[] | select(.value != null) |
+                "-p \(.value[0].HostPort):\(.key | split("/")[0])"),
+            ((.Config.Env // [])[] | "-e \(. | @sh)"),
+            ((.Mounts // [])[] | "-v \(.Name // .Source):\(.Destination)"),
+            ((.Config.Labels // {}) | to_entries[] |
+                select(.key | startswith("com.docker.compose") | not) |
+                "--label \("\(.key)=\(.value)" | @sh)"),
+            ((.NetworkSettings.Networks // {}) | keys[] | select(. != "bridge") |
+                "--network \(.)"),
+            .Config.Image,
+            ((.Config.Cmd // []) | map(@sh) | join(" "))
+        ]
+        | map(select(. != "")) | join(" \\\n  ")
+    '
+}
+
+# Blue-green redeploy for a standalone container: start the new image
+# alongside the old one, wait for health, then swap and retire
+# Args: $1 = container number, --image <ref> for the replacement image
+# Usage: d c bluegreen 2 --image repo/app:new [--timeout 120]
+container_bluegreen() {
+    local token="" new_image="" timeout=120
+
+    while [[ $# -gt 0 ]]; do
+        if [[ "$1" =~ ^(--image|--timeout)$ && $# -lt 2 ]]; then
+            echo -e "${C_ERROR}✗ ${1} needs a value${C_RESET}" >&2
+            return 1
+        fi
+        case "$1" in
+            --image)   new_image="$2"; shift 2 ;;
+            --image=*) new_image="${1#*=}"; shift ;;
+            --timeout) timeout="${2%s}"; shift 2 ;;
+            *) token="$1"; shift ;;
+        esac
+    done
+
+    if [[ -z "$token" || -z "$new_image" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d c bluegreen <number> --image <repo:tag>${C_RESET}" >&2
+        return 1
+    fi
+
+    local id
+    id=$(d_resolve_container "$token") || return 1
+    local name old_image
+    name=$(d_container_name "$id")
+    old_image=$(docker_cmd inspect --format '{{.Config.Image}}' "$id")
+
+    # Reconstruct the run command, swapping image and name and dropping
+    # published ports so green can start next to blue
+    local run_cmd green_cmd
+    run_cmd=$(container_runcmd "$token" | tr -d '\\')
+    green_cmd=$(echo "$run_cmd" \
+        | sed "s|--name ${name}|--name ${name}-green|" \
+        | sed -E 's/-p [0-9.:]+:[0-9]+ //g' \
+        | sed "s|${old_image}|${new_image}|")
+
+    echo -e "${C_INFO}ℹ Starting ${name}-green from ${new_image}...${C_RESET}"
+    # Run through docker_cmd so --dry-run and --context/--host routing
+    # apply to the run step, not just the rm steps
+    if ! eval "docker_cmd ${green_cmd#docker }" > /dev/null; then
+        echo -e "${C_ERROR}✗ Green container failed to start${C_RESET}" >&2
+        return 1
+    fi
+
+    # Wait for the green container to become healthy (or stay running)
+    local deadline=$(( $(date +%s) + timeout ))
+    local state
+    while true; do
+        state=$(docker_cmd inspect --format \
+            '{{if .State.Health}}{{.State.Health.Status}}{{else}}{{.State.Status}}{{end}}' \
+            "${name}-green" 2>/dev/null)
+        [[ "$state" == "healthy" || "$state" == "running" ]] && break
+        if [[ $(date +%s) -ge $deadline ]]; then
+            echo -e "${C_ERROR}✗ ${name}-green never became healthy (${state}) — keeping blue${C_RESET}" >&2
+            docker_cmd rm -f "${name}-green" > /dev/null
+            return 1
+        fi
+        sleep 2
+    done
+
+    # Give health-checked containers time to actually pass their check
+    if docker_cmd inspect --format '{{if .State.Health}}yes{{end}}' "${name}-green" | grep -q yes; then
+        while [[ "$state" != "healthy" ]]; do
+            if [[ $(date +%s) -ge $deadline ]]; then
+                echo -e "${C_ERROR}✗ ${name}-green stuck in '${state}' — keeping blue${C_RESET}" >&2
+                docker_cmd rm -f "${name}-green" > /dev/null
+                return 1
+            fi
+            sleep 2
+            state=$(docker_cmd inspect --format '{{.State.Health.Status}}' "${name}-green" 2>/dev/null)
+        done
+    fi
+
+    echo -e "${C_INFO}ℹ Green healthy — swapping published ports to the new image...${C_RESET}"
+    docker_cmd rm -f "$id" > /dev/null
+    docker_cmd rm -f "${name}-green" > /dev/null
+
+    # Final container: original name and ports, new image
+    local final_cmd
+    final_cmd=$(echo "$run_cmd" | sed "s|${old_image}|${new_image}|")
+    if eval "docker_cmd ${final_cmd#docker }" > /dev/null; then
+        echo -e "${C_SUCCESS}✓ ${name} now running ${new_image}${C_RESET}"
+        d_refresh_containers
+    else
+        echo -e "${C_ERROR}✗ Final swap failed — recreate manually: ${final_cmd}${C_RESET}" >&2
+        return 1
+    fi
+}
+
+# Remove a container and recreate it with identical configuration
+# Args: $1 = container number, optionally --pull
+# Usage: d c recreate 3 [--pull]
+container_recreate() {
+    local pull=false
+    local token=""
+
+    while [[ $# -gt 0 ]]; do
+        case "$1" in
+            --pull) pull=true; shift ;;
+            *) token="$1"; shift ;;
+        esac
+    done
+
+    local id
+    id=$(d_resolve_container "$token") || return 1
+    local name image
+    name=$(d_container_name "$id")
+    image=$(docker_cmd inspect --format '{{.Config.Image}}' "$id")
+
+    # Capture the reconstructed run command before the container goes away
+    local run_cmd
+    run_cmd=$(container_runcmd "$token" | tr -d '\\')
+    if [[ -z "$run_cmd" ]]; then
+        echo -e "${C_ERROR}✗ Could not reconstruct run command for ${name}${C_RESET}" >&2
+        return 1
+    fi
+
+    if [[ "$pull" == true ]]; then
+        echo -e "${C_INFO}ℹ Pulling ${image}...${C_RESET}"
+        docker_cmd pull "$image" || return 1
+    fi
+
+    echo -e "${C_INFO}ℹ Removing ${name}...${C_RESET}"
+    docker_cmd rm -f "$id" > /dev/null || return 1
+
+    echo -e "${C_INFO}ℹ Recreating ${name}...${C_RESET}"
+    # Run through docker_cmd so --dry-run and --context/--host routing
+    # hold for the run step as well as the rm
+    if eval "docker_cmd ${run_cmd#docker }" > /dev/null; then
+        echo -e "${C_SUCCESS}✓ Recreated ${name}${C_RESET}"
+        d_refresh_containers
+    else
+        echo -e "${C_ERROR}✗ Recreate failed — the old container was already removed${C_RESET}" >&2
+        echo -e "${C_SUBTLE}Run it manually: ${run_cmd}${C_RESET}" >&2
+        return 1
+    fi
+}
+
+# Generate a compose file from running containers
+# Args: selection tokens, optionally followed by -o <file>
+# Usage: d c to-compose 1 3 5 -o docker-compose.yml
+container_to_compose() {
+    local output=""
+    local tokens=()
+
+    while [[ $# -gt 0 ]]; do
+        case "$1" in
+            -o)
+                [[ $# -ge 2 ]] || { echo -e "${C_ERROR}✗ -o needs a file${C_RESET}" >&2; return 1; }
+                output="$2"; shift 2 ;;
+            *) tokens+=("$1"); shift ;;
+        esac
+    done
+
+    if [[ ${#tokens[@]} -eq 0 ]]; then
+        echo -e "${C_ERROR}✗ Usage: d c to-compose <numbers...> [-o file]${C_RESET}" >&2
+        return 1
+    fi
+
+    local ids=() token id
+    while IFS= read -r token; do
+        id=$(d_resolve_container "$token") || return 1
+        ids+=("$id")
+    done < <(d_expand_selection "${tokens[@]}")
+
+    local yaml="services:"
+    local networks=""
+    for id in "${ids[@]}"; do
+        yaml+=$'\n'"$(_container_compose_service "$id")"
+        # Collect non-default networks for the top-level networks key
+        local nets
+        nets=$(docker_cmd inspect "$id" | jq -r '.[0].NetworkSettings.Networks | keys[] | select(. != "bridge")')
+        [[ -n "$nets" ]] && networks+="$nets"$'\n'
+    done
+
+    if [[ -n "$networks" ]]; then
+        yaml+=$'\n'"networks:"
+        local net
+        while IFS= read -r net; do
+            [[ -z "$net" ]] && continue
+            yaml+=$'\n'"  ${net}:"$'\n'"    external: true"
+        done < <(echo "$networks" | sort -u)
+    fi
+
+    if [[ -n "$output" ]]; then
+        echo "$yaml" > "$output"
+        echo -e "${C_SUCCESS}✓ Wrote ${output} (${#ids[@]} service(s))${C_RESET}"
+    else
+        echo "$yaml"
+    fi
+}
+
+# Interactive docker cp: browse container paths and a host destination
+# Args: $1 = container number
+# Usage: d c cp -i 2  (container → host)
+#        d c cp -i 2 --to-container  (host → container)
+container_cp_interactive() {
+    local to_container=false
+    local token=""
+
+    while [[ $# -gt 0 ]]; do
+        case "$1" in
+            --to-container) to_container=true; shift ;;
+            *) token="$1"; shift ;;
+        esac
+    done
+
+    if [[ -z "$token" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d c cp -i <number> [--to-container]${C_RESET}" >&2
+        return 1
+    fi
+
+    local id
+    id=$(d_resolve_container "$token") || return 1
+    local name
+    name=$(d_container_name "$id")
+
+    if [[ "$to_container" == true ]]; then
+        # host → container
+        local src
+        if command -v fzf &> /dev/null; then
+            src=$(find . -maxdepth 4 -type f -not -path '*/.git/*' 2>/dev/null \
+                | fzf --prompt="host file > " --height=60% --reverse)
+        else
+            echo -en "${C_INFO}Host file to copy: ${C_RESET}" >&2
+            read -r src
+        fi
+        [[ -z "$src" ]] && return 1
+
+        local dest
+        dest=$(_container_pick_path "$id") || return 1
+        [[ -z "$dest" ]] && return 1
+
+        echo -e "${C_INFO}ℹ Copying ${src} → ${name}:${dest}${C_RESET}"
+        docker_cmd cp "$src" "${id}:${dest}"
+    else
+        # container → host
+        local src
+        src=$(_container_pick_path "$id") || return 1
+        [[ -z "$src" ]] && return 1
+
+        local dest
+        dest=$(_host_pick_path)
+        [[ -z "$dest" ]] && dest="."
+
+        echo -e "${C_INFO}ℹ Copying ${name}:${src} → ${dest}${C_RESET}"
+        docker_cmd cp "${id}:${src}" "$dest"
+    fi
+}
+
+# ═══════════════════════════════════════════════════════════════
+# INSPECT
+# ═══════════════════════════════════════════════════════════════
+
+# One labelled line of inspect output
+# Args: $1 = label, $2 = value (empty values are skipped)
+_inspect_line() {
+    [[ -z "$2" || "$2" == "null" ]] && return 0
+    printf "  ${C_SUBTLE}%-14s${C_RESET} %s\n" "$1" "$2"
+}
+
+# Readable, colorized summary of docker inspect for one container
+# Args: $1 = selection token, optionally --raw for the plain JSON
+# Usage: d c inspect 2 [--raw]
+container_inspect() {
+    local raw=false token=""
+    local arg
+    for arg in "$@"; do
+        case "$arg" in
+            --raw) raw=true ;;
+            *) token="$arg" ;;
+        esac
+    done
+
+    if [[ -z "$token" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d c inspect <number> [--raw]${C_RESET}" >&2
+        return 1
+    fi
+
+    local id
+    id=$(d_resolve_container "$token") || return 1
+
+    local json
+    json=$(docker_capture inspect "$id") || {
+        echo -e "${C_ERROR}✗ Could not inspect container${C_RESET}" >&2
+        return 1
+    }
+
+    if [[ "$raw" == true ]]; then
+        echo "$json"
+        return 0
+    fi
+
+    local name
+    name=$(echo "$json" | jq -r '.[0].Name | ltrimstr("/")')
+    echo -e "${C_CONTRAST}${name}${C_RESET} ${C_MUTED}(${id:0:12})${C_RESET}"
+
+    _inspect_line "Image"   "$(echo "$json" | jq -r '.[0].Config.Image')"
+    _inspect_line "Command" "$(echo "$json" | jq -r '[.[0].Path] + .[0].Args | join(" ")')"
+    _inspect_line "Status"  "$(echo "$json" | jq -r '.[0].State.Status')"
+    _inspect_line "Started" "$(echo "$json" | jq -r '.[0].State.StartedAt | split(".")[0]')"
+    _inspect_line "Restart" "$(echo "$json" | jq -r '.[0].HostConfig.RestartPolicy.Name // empty')"
+    _inspect_line "Health"  "$(echo "$json" | jq -r '.[0].State.Health.Status // empty')"
+    _inspect_line "IP"      "$(echo "$json" | jq -r '[.[0].NetworkSettings.Networks[].IPAddress] | map(select(. != "")) | join(", ")')"
+
+    local section
+    section=$(echo "$json" | jq -r '.[0].Config.Env[]? // empty')
+    if [[ -n "$section" ]]; then
+        echo -e "  ${C_SUBTLE}Env${C_RESET}"
+        echo "$section" | sed "s/^/    /"
+    fi
+
+    section=$(echo "$json" | jq -r '.[0].Mounts[]? | "\(.Source) → \(.Destination)\(if .RW then "" else " (ro)" end)"')
+    if [[ -n "$section" ]]; then
+        echo -e "  ${C_SUBTLE}Mounts${C_RESET}"
+        echo "$section" | sed "s/^/    /"
+    fi
+
+    section=$(echo "$json" | jq -r '.[0].NetworkSettings.Networks | keys[]? // empty')
+    if [[ -n "$section" ]]; then
+        echo -e "  ${C_SUBTLE}Networks${C_RESET}"
+        echo "$section" | sed "s/^/    /"
+    fi
+
+    section=$(echo "$json" | jq -r '.[0].NetworkSettings.Ports | to_entries[]? | select(.value != null) | "\(.value[0].HostPort) → \(.key)"')
+    if [[ -n "$section" ]]; then
+        echo -e "  ${C_SUBTLE}Ports${C_RESET}"
+        echo "$section" | sed "s/^/    /"
+    fi
+}
+
+# Copy files to/from a container addressed by selection token, e.g.
+#   d c cp 2:/var/log/app.log ./
+#   d c cp ./config.yml @web:/etc/app/
+# Tokens before the colon go through the usual number/alias/name
+# resolution; plain paths pass through untouched.
+# Args: $1 = source, $2 = destination
+container_cp() {
+    if [[ $# -lt 2 ]]; then
+        echo -e "${C_ERROR}✗ Usage: d c cp <src> <dest>  (either side may be <number>:<path>)${C_RESET}" >&2
+        return 1
+    fi
+
+    local resolved=()
+    local arg
+    for arg in "$@"; do
+        if [[ "$arg" == *:* && "$arg" != /* && "$arg" != ./* ]]; then
+            local token="${arg%%:*}" path="${arg#*:}"
+            local id
+            id=$(d_resolve_container "$token") || return 1
+            resolved+=("${id}:${path}")
+        else
+            resolved+=("$arg")
+        fi
+    done
+
+    docker_cmd cp "${resolved[@]}"
+}
+
+# ═══════════════════════════════════════════════════════════════
+# ATTACH
+# ═══════════════════════════════════════════════════════════════
+
+# Attach the terminal to a numbered container's stdio
+# Args: $1 = selection token
+# Usage: d c attach 1
+container_attach() {
+    if [[ -z "${1:-}" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d c attach <number>${C_RESET}" >&2
+        return 1
+    fi
+
+    local id
+    id=$(d_resolve_container "$1") || return 1
+
+    echo -e "${C_INFO}ℹ Attaching to $(d_container_name "$id") — detach with Ctrl-p Ctrl-q${C_RESET}"
+    docker_cmd attach --sig-proxy=true "$id"
+}
+# Show the process list inside a numbered container
+# Args: $1 = selection token, rest = ps options passed through
+# Usage: d c top 2
+container_top() {
+    if [[ -z "${1:-}" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d c top <number>${C_RESET}" >&2
+        return 1
+    fi
+
+    local id
+    id=$(d_resolve_container "$1") || return 1
+    shift
+
+    local output
+    output=$(docker_capture top "$id" "$@") || {
+        echo -e "${C_ERROR}✗ Could not read processes for $(d_container_name "$id")${C_RESET}" >&2
+        return 1
+    }
+
+    echo -e "${C_CONTRAST}$(d_container_name "$id")${C_RESET}"
+    # Header row dimmed, process rows aligned as docker printed them
+    echo -e "${C_SUBTLE}$(echo "$output" | head -1)${C_RESET}"
+    echo "$output" | tail -n +2
+}
+
+# Export a container's filesystem to a tar archive
+# Args: $1 = selection token, -o <file> (defaults to <name>.tar)
+# Usage: d c export 3 -o app.tar
+container_export() {
+    local out="" token=""
+
+    while [[ $# -gt 0 ]]; do
+        case "$1" in
+            -o|--output) out="$2"; shift ;;
+            *)           token="$1" ;;
+        esac
+        shift
+    done
+
+    if [[ -z "$token" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d c export <number> [-o file.tar]${C_RESET}" >&2
+        return 1
+    fi
+
+    local id
+    id=$(d_resolve_container "$token") || return 1
+    local name
+    name=$(d_container_name "$id")
+    [[ -z "$out" ]] && out="${name}.tar"
+
+    if docker_cmd export -o "$out" "$id"; then
+        echo -e "${C_SUCCESS}✓ Exported ${name} → ${out} ($(du -h "$out" 2>/dev/null | awk '{ print $1 }'))${C_RESET}"
+    else
+        echo -e "${C_ERROR}✗ Export failed for ${name}${C_RESET}" >&2
+        return 1
+    fi
+}
+
+# Adjust resource limits on running containers selected by number;
+# every flag passes straight through to docker update
+# Args: selection tokens plus docker update flags
+# Usage: d c update 2 --memory 512m --cpus 1.5
+container_update() {
+    local tokens=()
+    local flags=()
+
+    local arg
+    for arg in "$@"; do
+        case "$arg" in
+            -*) flags+=("$arg") ;;
+            *)
+                # Flag values look like tokens; anything following a
+                # flag that expects a value belongs to the flags
+                if [[ ${#flags[@]} -gt 0 && "${flags[${#flags[@]}-1]}" != *=* ]]; then
+                    case "${flags[${#flags[@]}-1]}" in
+                        --restart|--memory|-m|--memory-swap|--cpus|--cpu-shares|--cpuset-cpus|--pids-limit)
+                            flags+=("$arg")
+                            continue
+                            ;;
+                    esac
+                fi
+                tokens+=("$arg")
+                ;;
+        esac
+    done
+
+    if [[ ${#tokens[@]} -eq 0 || ${#flags[@]} -eq 0 ]]; then
+        echo -e "${C_ERROR}✗ Usage: d c update <numbers...> --memory 512m --cpus 1.5 ...${C_RESET}" >&2
+        return 1
+    fi
+
+    local token id rc=0
+    while IFS= read -r token; do
+        id=$(d_resolve_container "$token") || { rc=1; continue; }
+        if docker_cmd update "${flags[@]}" "$id" > /dev/null; then
+            echo -e "${C_SUCCESS}✓ Updated $(d_container_name "$id") (${flags[*]})${C_RESET}"
+        else
+            echo -e "${C_ERROR}✗ Update failed for $(d_container_name "$id")${C_RESET}" >&2
+            rc=1
+        fi
+    done < <(d_expand_selection "${tokens[@]}")
+    return $rc
+}
+
+# Print a container's published ports as a table with copy-paste URLs
+# Args: $1 = selection token
+# Usage: d c port 2
+container_port() {
+    if [[ -z "${1:-}" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d c port <number>${C_RESET}" >&2
+        return 1
+    fi
+
+    local id
+    id=$(d_resolve_container "$1") || return 1
+
+    local raw
+    raw=$(docker_capture port "$id") || return 1
+    if [[ -z "$raw" ]]; then
+        echo -e "${C_MUTED}No published ports for $(d_container_name "$id")${C_RESET}"
+        return 0
+    fi
+
+    printf "${C_SUBTLE}%-14s %-22s %s${C_RESET}\n" "CONTAINER" "HOST" "URL"
+    # Lines look like: 80/tcp -> 0.0.0.0:8080
+    local cport _arrow haddr
+    while read -r cport _arrow haddr; do
+        [[ -z "$cport" ]] && continue
+        local hport="${haddr##*:}"
+        local url="-"
+        [[ "$cport" == */tcp ]] && url="http://localhost:${hport}"
+        printf "${C_CONTRAST}%-14s${C_RESET} %-22s ${C_GLOW}%s${C_RESET}\n" "$cport" "$haddr" "$url"
+    done <<< "$raw"
+}
+
+# Block until the selected containers exit and report their codes.
+# Exits non-zero when any container does, so scripts can chain on it.
+# Args: selection tokens
+# Usage: d c wait 1 2
+container_wait() {
+    if [[ $# -eq 0 ]]; then
+        echo -e "${C_ERROR}✗ Usage: d c wait <numbers...>${C_RESET}" >&2
+        return 1
+    fi
+
+    local ids=() token id
+    while IFS= read -r token; do
+        id=$(d_resolve_container "$token") || return 1
+        ids+=("$id")
+    done < <(d_expand_selection "$@")
+
+    local rc=0 code
+    for id in "${ids[@]}"; do
+        code=$(docker_capture wait "$id") || { rc=1; continue; }
+        if [[ "$code" == "0" ]]; then
+            echo -e "${C_SUCCESS}✓ $(d_container_name "$id") exited 0${C_RESET}"
+        else
+            echo -e "${C_WARNING}⚠ $(d_container_name "$id") exited ${code}${C_RESET}"
+            rc=1
+        fi
+    done
+    return $rc
+}
+
+# ═══════════════════════════════════════════════════════════════
+# EXEC
+# ═══════════════════════════════════════════════════════════════
+
+# Run a command inside a numbered container. Flags are translated to
+# the underlying docker exec; everything after the command name is
+# handed over untouched.
+# Args: $1 = selection token, then optional flags, then the command
+# Usage: d c exec 2 --user app --workdir /srv -e DEBUG=1 ls -la
+container_exec() {
+    local token="${1:-}"
+    shift 2>/dev/null || true
+
+    if [[ -z "$token" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d c exec <number> [--user U] [--workdir DIR] [-e K=V] [-d] <cmd...>${C_RESET}" >&2
+        return 1
+    fi
+
+    local id
+    id=$(d_resolve_container "$token") || return 1
+
+    local exec_args=()
+    while [[ $# -gt 0 ]]; do
+        if [[ "$1" =~ ^(--user|-u|--workdir|-w|-e)$ && $# -lt 2 ]]; then
+            echo -e "${C_ERROR}✗ ${1} needs a value${C_RESET}" >&2
+            return 1
+        fi
+        case "$1" in
+            --user|-u)    exec_args+=(--user "$2"); shift 2 ;;
+            --user=*)     exec_args+=(--user "${1#*=}"); shift ;;
+            --workdir|-w) exec_args+=(--workdir "$2"); shift 2 ;;
+            --workdir=*)  exec_args+=(--workdir "${1#*=}"); shift ;;
+            -e)           exec_args+=(-e "$2"); shift 2 ;;
+            -e=*)         exec_args+=(-e "${1#*=}"); shift ;;
+            -d|--detach)  exec_args+=(-d); shift ;;
+            --)           shift; break ;;
+            *)            break ;;
+        esac
+    done
+
+    if [[ $# -eq 0 ]]; then
+        echo -e "${C_ERROR}✗ No command given${C_RESET}" >&2
+        return 1
+    fi
+
+    # Interactive TTY unless detached or piped
+    if [[ " ${exec_args[*]+"${exec_args[*]}"} " != *" -d "* && -t 0 && -t 1 ]]; then
+        exec_args+=(-it)
+    fi
+
+    docker_cmd exec "${exec_args[@]+"${exec_args[@]}"}" "$id" "$@"
+}
+
+# ═══════════════════════════════════════════════════════════════
+# CONTAINER SHELL (d cd)
+# ═══════════════════════════════════════════════════════════════
+
+# Figure out the best interactive shell available in a container.
+# Preference order: per-image config (cd.shell.<image>), global config
+# (cd.shell), then whatever of bash/zsh/ash/sh the container has.
+# Args: $1 = container ID
+# Output: shell path or name
+_container_detect_shell() {
+    local id="$1"
+
+    local image
+    image=$(docker_capture inspect --format '{{.Config.Image}}' "$id" 2>/dev/null)
+    image="${image%%:*}"
+    image="${image##*/}"
+
+    local pref
+    pref=$(d_conf_get "cd.shell.${image}" "")
+    [[ -z "$pref" ]] && pref=$(d_conf_get "cd.shell" "")
+
+    local candidates=()
+    [[ -n "$pref" ]] && candidates+=("$pref")
+    candidates+=(bash zsh ash sh)
+
+    # One probe instead of noisy failed exec attempts per shell
+    local found
+    found=$(docker_capture exec "$id" sh -c \
+        "command -v ${candidates[*]} 2>/dev/null" 2>/dev/null)
+
+    local want
+    for want in "${candidates[@]}"; do
+        local hit
+        hit=$(echo "$found" | grep -m1 "/${want}\$")
+        if [[ -n "$hit" ]]; then
+            echo "$hit"
+            return 0
+        fi
+    done
+    return 1
+}
+
+# Open an interactive shell in a container, reporting which shell was
+# picked. Accepts numbers, unique name prefixes, and fuzzy substrings;
+# ambiguous names drop into a picker instead of erroring
+# Args: $1 = selection token
+# Usage: d cd 2 / d cd web
+container_cd() {
+    if [[ -z "${1:-}" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d cd <number|name>${C_RESET}" >&2
+        return 1
+    fi
+
+    local id
+    if ! id=$(d_resolve_container "$1" 2>/dev/null); then
+        # Several containers may match a fuzzy name — disambiguate
+        # interactively rather than bailing
+        local matches
+        matches=$(awk -F'\t' -v t="$1" 'index($3, t) > 0 { printf "%s  %s\n", $1, $3 }' \
+            "$D_CONTAINER_CACHE" 2>/dev/null)
+        if [[ $(echo "$matches" | grep -c .) -gt 1 ]] && command -v fzf &> /dev/null; then
+            local picked
+            picked=$(echo "$matches" \
+                | fzf --prompt="which container > " --height=40% --reverse \
+                | awk '{ print $1 }')
+            [[ -z "$picked" ]] && return 1
+            id=$(d_resolve_container "$picked") || return 1
+        else
+            # Re-run for the normal error message (ambiguity listing
+            # or not-found hint)
+            d_resolve_container "$1" > /dev/null
+            return 1
+        fi
+    fi
+
+    local shell
+    if ! shell=$(_container_detect_shell "$id"); then
+        echo -e "${C_ERROR}✗ No usable shell found in $(d_container_name "$id")${C_RESET}" >&2
+        return 1
+    fi
+
+    echo -e "${C_INFO}ℹ Entering $(d_container_name "$id") with ${shell}${C_RESET}"
+    docker_cmd exec -it "$id" "$shell"
+}
@@ -0,0 +1,537 @@
+#!/usr/bin/env bash
+#
+# Termflix Docker Core Module
+# Shared helpers for the `d` docker shortcuts: numbered listings and
+# number-to-ID resolution for containers and other resources
+#
+
+# Prevent multiple sourcing
+[[ -n "${_TERMFLIX_DOCKER_CORE_LOADED:-}" ]] && return 0
+_TERMFLIX_DOCKER_CORE_LOADED=1
+
+# ═══════════════════════════════════════════════════════════════
+# RUNTIME DETECTION
+# ═══════════════════════════════════════════════════════════════
+
+# Pick the container runtime CLI. Order of precedence: DOCKER_BIN
+# env var, runtime.backend in the config, then auto-detection so the
+# same numbered shortcuts work on Podman/nerdctl hosts.
+_d_detect_backend() {
+    local configured
+    configured=$(d_conf_get "runtime.backend" "" 2>/dev/null)
+    if [[ -n "$configured" ]]; then
+        echo "$configured"
+        return 0
+    fi
+
+    local candidate
+    for candidate in docker podman nerdctl; do
+        if command -v "$candidate" &> /dev/null; then
+            echo "$candidate"
+            return 0
+        fi
+    done
+    echo "docker"
+}
+
+# Runtime CLI binary (overridable for testing)
+DOCKER_BIN="${DOCKER_BIN:-$(_d_detect_backend)}"
+
+# Verify the runtime CLI is available
+# Returns: 0 if found, 1 otherwise (with error message)
+check_docker_bin() {
+    if ! command -v "$DOCKER_BIN" &> /dev/null; then
+        echo -e "${C_ERROR}✗ ${DOCKER_BIN} not found. Install docker, podman, or nerdctl first.${C_RESET}" >&2
+        return 1
+    fi
+    return 0
+}
+
+# Read-only docker verbs that still execute under --dry-run, so
+# listings and number resolution keep working
+_d_readonly_verb() {
+    case "$1" in
+        ps|images|inspect|logs|diff|history|top|port|events|stats|version|info|search) return 0 ;;
+        volume|network|system|compose|context)
+            # Sub-verbs: only their listing/inspect forms are read-only
+            case "$2" in
+                ls|inspect|df|version|ps|config) return 0 ;;
+            esac
+            return 1
+            ;;
+    esac
+    return 1
+}
+
+# Run a docker command. Under --dry-run, mutating commands are printed
+# (with fully resolved IDs) instead of executed.
+# Args: all arguments are passed through to the docker CLI
+docker_cmd() {
+    if [[ "${D_DRY_RUN:-0}" == "1" ]] && ! _d_readonly_verb "$1" "${2:-}"; then
+        echo -e "${C_INFO}DRY-RUN:${C_RESET} ${DOCKER_BIN} $*" >&2
+        return 0
+    fi
+
+    # --host points every call at a remote daemon via DOCKER_HOST
+    # (ssh:// or tcp://) without changing the user's environment
+    if [[ -n "${D_HOST:-}" ]]; then
+        DOCKER_HOST="$D_HOST" "$DOCKER_BIN" "$@"
+        return $?
+    fi
+
+    # --context routes every call at a named docker context without
+    # touching the global default
+    if [[ -n "${D_CONTEXT:-}" ]]; then
+        "$DOCKER_BIN" --context "$D_CONTEXT" "$@"
+    else
+        "$DOCKER_BIN" "$@"
+    fi
+}
+
+# Run a docker command capturing stdout only. Stderr is held apart so
+# daemon warnings never reach callers that parse rows, and a non-zero
+# exit reports the real error instead of feeding it to the parser.
+# Args: all arguments are passed through to the docker CLI
+# Output: the command's stdout, untouched
+# Returns: the docker exit code
+docker_capture() {
+    local err_file="${D_CACHE_DIR}/stderr.$$"
+    init_docker_cache
+
+    # Same host/context routing as docker_cmd, so listings and the
+    # mutations resolved from them target the same daemon
+    local rc=0
+    if [[ -n "${D_HOST:-}" ]]; then
+        DOCKER_HOST="$D_HOST" "$DOCKER_BIN" "$@" 2> "$err_file" || rc=$?
+    elif [[ -n "${D_CONTEXT:-}" ]]; then
+        "$DOCKER_BIN" --context "$D_CONTEXT" "$@" 2> "$err_file" || rc=$?
+    else
+        "$DOCKER_BIN" "$@" 2> "$err_file" || rc=$?
+    fi
+
+    if [[ $rc -ne 0 ]]; then
+        echo -e "${C_ERROR}✗ docker $1 failed:${C_RESET}" >&2
+        sed 's/^/    /' "$err_file" >&2
+    elif [[ -s "$err_file" ]]; then
+        # Surface warnings without mixing them into parsed output
+        sed "s/^/${C_WARNING}⚠ /;s/\$/${C_RESET}/" "$err_file" >&2
+    fi
+
+    rm -f "$err_file" 2>/dev/null
+    return $rc
+}
+
+# ═══════════════════════════════════════════════════════════════
+# ENGINE API CLIENT
+# ═══════════════════════════════════════════════════════════════
+
+# Listings talk to the Engine REST API over the unix socket when
+# possible — structured JSON instead of parsing CLI text — with the
+# exec path kept as a fallback.
+[[ -n "${D_API_SOCKET:-}" ]] && D_API_SOCKET_SET=1
+D_API_SOCKET="${D_API_SOCKET:-/var/run/docker.sock}"
+D_API_VERSION="v1.43"
+
+# Check whether the Engine API is reachable via the socket
+d_api_available() {
+    [[ "${D_NO_API:-0}" == "1" ]] && return 1
+    # The socket is always the local daemon — remote targeting via
+    # --context/--host must go through the CLI
+    [[ -n "${D_CONTEXT:-}" || -n "${D_HOST:-}" ]] && return 1
+    # Non-docker backends go through their own CLI unless the user
+    # points D_API_SOCKET at a compatible socket themselves
+    [[ "$DOCKER_BIN" != "docker" && -z "${D_API_SOCKET_SET:-}" ]] && return 1
+    command -v curl &> /dev/null || return 1
+    [[ -S "$D_API_SOCKET" ]] || return 1
+    return 0
+}
+
+# Issue an Engine API request
+# Args: $1 = HTTP method, $2 = path (e.g. /containers/json?all=true)
+# Output: response body (JSON)
+docker_api() {
+    local method="$1" path="$2"
+    curl -s --unix-socket "$D_API_SOCKET" -X "$method" \
+        "http://localhost/${D_API_VERSION}${path}" 2>/dev/null
+}
+
+# Container rows from the Engine API, in the same tab format the CLI
+# path produces (id, name, image, status)
+_d_containers_via_api() {
+    docker_api GET "/containers/json?all=true" | jq -r '
+        .[] | [
+            .Id[0:12],
+            (.Names[0] | ltrimstr("/")),
+            .Image,
+            .Status
+        ] | @tsv' 2>/dev/null
+}
+
+# Image rows from the Engine API (id, repo:tag, size)
+_d_images_via_api() {
+    docker_api GET "/images/json" | jq -r '
+        .[] | [
+            (.Id | sub("^sha256:"; "")[0:12]),
+            (.RepoTags[0] // "<none>:<none>"),
+            ((.Size / 1048576) | floor | tostring + "MB")
+        ] | @tsv' 2>/dev/null
+}
+
+# ═══════════════════════════════════════════════════════════════
+# SELECTION CACHE
+# ═══════════════════════════════════════════════════════════════
+
+# Porcelain mode: stable tab-separated machine output, no colors.
+# Format v1 fields per row: <resource> <num> <id-or-name> <name> <extra...>
+D_PORCELAIN="${D_PORCELAIN:-0}"
+
+# Numbered listings are cached per shell session so that `d c rm 3`
+# resolves against the exact list rendered in *this* shell, even if
+# another terminal has refreshed its own listing since.
+# D_SHELL_ID can be exported by a shell wrapper; the parent PID is a
+# good default because each `d` invocation is a child of the shell.
+D_SHELL_ID="${D_SHELL_ID:-$PPID}"
+D_CACHE_DIR="/tmp/termflix_docker_$(id -u)/shell_${D_SHELL_ID}"
+D_CONTAINER_CACHE="${D_CACHE_DIR}/containers"
+
+# Make sure the cache directory exists
+init_docker_cache() {
+    mkdir -p "$D_CACHE_DIR" 2>/dev/null
+    chmod 700 "$D_CACHE_DIR" 2>/dev/null
+}
+
+# Validate that a listing row has the expected tab-separated field
+# count, so error text from a failing docker never becomes a row
+# Args: $1 = row, $2 = expected field count
+d_valid_row() {
+    [[ -n "$1" ]] && [[ $(echo "$1" | awk -F'\t' '{ print NF }') -eq "$2" ]]
+}
+
+# Print the error banner shown when a listing source failed
+# Args: $1 = resource label (containers, images, ...)
+d_listing_error() {
+    echo -e "${C_ERROR}✗ Could not list ${1} — is the docker daemon running and do you have permission?${C_RESET}" >&2
+}
+
+# Refresh the container cache from docker ps
+# Cache format (tab-separated): num, id, name, image, status
+# Returns: 1 if the listing source errored (cache left empty)
+d_refresh_containers() {
+    init_docker_cache
+
+    local raw rc=0
+    if d_api_available; then
+        raw=$(_d_containers_via_api)
+        # Fall back to the CLI when the API path yields nothing usable
+        if [[ -z "$raw" ]]; then
+            raw=$(docker_capture ps -a --format '{{.ID}}\t{{.Names}}\t{{.Image}}\t{{.Status}}') || rc=$?
+        fi
+    else
+        raw=$(docker_capture ps -a --format '{{.ID}}\t{{.Names}}\t{{.Image}}\t{{.Status}}') || rc=$?
+    fi
+
+    : > "$D_CONTAINER_CACHE"
+    if [[ $rc -ne 0 ]]; then
+        return 1
+    fi
+
+    local num=1
+    local line
+    local deferred=()
+
+    # Favorites are numbered first so their positions stay stable
+    while IFS= read -r line; do
+        d_valid_row "$line" 4 || continue
+        local name
+        name=$(echo "$line" | cut -f2)
+        if d_fav_has_name "$name" 2>/dev/null; then
+            printf '%d\t%s\n' "$num" "$line" >> "$D_CONTAINER_CACHE"
+            ((num++))
+        else
+            deferred+=("$line")
+        fi
+    done <<< "$raw"
+
+    for line in "${deferred[@]}"; do
+        printf '%d\t%s\n' "$num" "$line" >> "$D_CONTAINER_CACHE"
+        ((num++))
+    done
+
+    _d_container_state_hash > "${D_CONTAINER_CACHE}.hash"
+}
+
+# Hash of the current container set, used to detect when the cached
+# numbered listing has gone stale
+_d_container_state_hash() {
+    docker_cmd ps -aq --no-trunc 2>/dev/null | sort | cksum | awk '{ print $1 }'
+}
+
+# Verify the cached listing still matches reality before trusting a
+# number. Prevents off-by-one deletions after containers come or go.
+# Returns: 1 (with an error) when the snapshot is stale
+d_check_container_cache() {
+    [[ -f "${D_CONTAINER_CACHE}.hash" ]] || return 0
+
+    local cached current
+    cached=$(cat "${D_CONTAINER_CACHE}.hash")
+    current=$(_d_container_state_hash)
+
+    if [[ "$cached" != "$current" ]]; then
+        echo -e "${C_ERROR}✗ Containers changed since the last listing — numbers may be off. Re-run 'd ls' first.${C_RESET}" >&2
+        return 1
+    fi
+    return 0
+}
+
+# Resolve a selection token (listing number) to a container ID
+# Args: $1 = token from the command line
+# Output: container ID on stdout
+# Returns: 1 if the token cannot be resolved
+d_resolve_container() {
+    local token="$1"
+
+    # Refresh on demand so numbers work without a prior `d ls`
+    [[ -f "$D_CONTAINER_CACHE" ]] || d_refresh_containers
+
+    # "last" (or -l) resolves to the most recently created container,
+    # mirroring docker ps -l
+    if [[ "$token" == "last" || "$token" == "-l" ]]; then
+        local last_id
+        last_id=$(docker_cmd ps -l --format '{{.ID}}')
+        if [[ -n "$last_id" ]]; then
+            echo "$last_id"
+            return 0
+        fi
+        echo -e "${C_ERROR}✗ No containers exist yet${C_RESET}" >&2
+        return 1
+    fi
+
+    # @alias tokens resolve through the favorites store by name, so
+    # they survive container recreation
+    if [[ "$token" == @* ]]; then
+        local fav_name
+        fav_name=$(d_fav_lookup "${token#@}" 2>/dev/null)
+        if [[ -n "$fav_name" ]]; then
+            local fav_id
+            fav_id=$(docker_cmd ps -a --filter "name=^${fav_name}$" --format '{{.ID}}' | head -1)
+            if [[ -n "$fav_id" ]]; then
+                echo "$fav_id"
+                return 0
+            fi
+            echo -e "${C_ERROR}✗ Favorite ${token} points at '${fav_name}' but no such container exists${C_RESET}" >&2
+            return 1
+        fi
+    fi
+
+    if [[ "$token" =~ ^[0-9]+$ ]]; then
+        d_check_container_cache || return 1
+        local id
+        id=$(awk -F'\t' -v n="$token" '$1 == n { print $2 }' "$D_CONTAINER_CACHE")
+        if [[ -n "$id" ]]; then
+            echo "$id"
+            return 0
+        fi
+    else
+        # Short ID prefixes pasted from other tools work too, as long
+        # as they uniquely match an ID in the current listing
+        if [[ "$token" =~ ^[0-9a-f]{2,64}$ ]]; then
+            local id_matches
+            id_matches=$(awk -F'\t' -v t="$token" 'index($2, t) == 1 { print $2 }' "$D_CONTAINER_CACHE")
+            if [[ $(echo "$id_matches" | grep -c .) -eq 1 ]]; then
+                echo "$id_matches"
+                return 0
+            fi
+        fi
+
+        # Fuzzy name resolution: unique prefix first, then substring
+        local matches
+        matches=$(awk -F'\t' -v t="$token" 'index($3, t) == 1 { print $2 "\t" $3 }' "$D_CONTAINER_CACHE")
+        if [[ -z "$matches" ]]; then
+            matches=$(awk -F'\t' -v t="$token" 'index($3, t) > 0 { print $2 "\t" $3 }' "$D_CONTAINER_CACHE")
+        fi
+
+        local count
+        count=$(echo "$matches" | grep -c .)
+        if [[ $count -eq 1 ]]; then
+            echo "$matches" | cut -f1
+            return 0
+        elif [[ $count -gt 1 ]]; then
+            echo -e "${C_ERROR}✗ '${token}' is ambiguous — candidates:${C_RESET}" >&2
+            echo "$matches" | cut -f2 | sed 's/^/    /' >&2
+            return 1
+        fi
+    fi
+
+    echo -e "${C_ERROR}✗ No container matches '${token}' — run 'd ls' to see the numbered list${C_RESET}" >&2
+    return 1
+}
+
+# Look up the cached name for a container ID
+# Args: $1 = container ID
+d_container_name() {
+    awk -F'\t' -v id="$1" '$2 == id { print $3 }' "$D_CONTAINER_CACHE" 2>/dev/null
+}
+
+# Expand selection tokens, including ranges like "1-3", into
+# individual tokens on stdout (one per line)
+# Args: all selection tokens
+d_expand_selection() {
+    local token i
+    for token in "$@"; do
+        if [[ "$token" =~ ^([0-9]+)-([0-9]+)$ ]]; then
+            for ((i = BASH_REMATCH[1]; i <= BASH_REMATCH[2]; i++)); do
+                echo "$i"
+            done
+        else
+            echo "$token"
+        fi
+    done
+}
+
+# ═══════════════════════════════════════════════════════════════
+# LISTINGS
+# ═══════════════════════════════════════════════════════════════
+
+# Unified listing across containers, images, volumes, and networks
+# with namespaced numbers (c1, i3, v2, n1) for use with `d rm`
+d_list_all() {
+    d_refresh_containers
+    d_refresh_images
+    d_refresh_volumes
+    d_refresh_networks
+
+    if [[ "$D_PORCELAIN" == "1" ]]; then
+        sed 's/^/container\t/' "$D_CONTAINER_CACHE"
+        sed 's/^/image\t/' "$D_IMAGE_CACHE"
+        sed 's/^/volume\t/' "$D_VOLUME_CACHE"
+        sed 's/^/network\t/' "$D_NETWORK_CACHE"
+        return 0
+    fi
+
+    echo -e "${C_PURPLE}Containers${C_RESET}"
+    local num rest
+    while IFS=$'\t' read -r num rest; do
+        echo -e "  ${C_GLOW}[c${num}]${C_RESET} $(echo "$rest" | awk -F'\t' '{ print $1 "  " $2 }')"
+    done < "$D_CONTAINER_CACHE"
+
+    echo -e "${C_PURPLE}Images${C_RESET}"
+    while IFS=$'\t' read -r num rest; do
+        echo -e "  ${C_GLOW}[i${num}]${C_RESET} $(echo "$rest" | awk -F'\t' '{ print $1 "  " $2 }')"
+    done < "$D_IMAGE_CACHE"
+
+    echo -e "${C_PURPLE}Volumes${C_RESET}"
+    while IFS=$'\t' read -r num rest; do
+        echo -e "  ${C_GLOW}[v${num}]${C_RESET} $(echo "$rest" | awk -F'\t' '{ print $1 }')"
+    done < "$D_VOLUME_CACHE"
+
+    echo -e "${C_PURPLE}Networks${C_RESET}"
+    while IFS=$'\t' read -r num rest; do
+        echo -e "  ${C_GLOW}[n${num}]${C_RESET} $(echo "$rest" | awk -F'\t' '{ print $2 }')"
+    done < "$D_NETWORK_CACHE"
+}
+
+# Remove resources selected with namespaced numbers from `d a`
+# Args: namespaced tokens (c2, i5, v1, n3)
+# Usage: d rm c2 i5 v1
+d_rm_namespaced() {
+    if [[ $# -eq 0 ]]; then
+        echo -e "${C_ERROR}✗ Usage: d rm <c#|i#|v#|n#>...${C_RESET}" >&2
+        return 1
+    fi
+
+    local token rc=0
+    for token in "$@"; do
+        local kind="${token:0:1}"
+        local num="${token:1}"
+        local target
+
+        case "$kind" in
+            c)
+                target=$(d_resolve_container "$num") || { rc=1; continue; }
+                docker_cmd rm -f "$target" > /dev/null \
+                    && echo -e "${C_SUCCESS}✓ Removed container $(d_container_name "$target")${C_RESET}" \
+                    || rc=1
+                ;;
+            i)
+                target=$(d_resolve_image "$num") || { rc=1; continue; }
+                docker_cmd rmi "$target" > /dev/null \
+                    && echo -e "${C_SUCCESS}✓ Removed image ${target}${C_RESET}" \
+                    || rc=1
+                ;;
+            v)
+                target=$(d_resolve_volume "$num") || { rc=1; continue; }
+                docker_cmd volume rm "$target" > /dev/null \
+                    && echo -e "${C_SUCCESS}✓ Removed volume ${target}${C_RESET}" \
+                    || rc=1
+                ;;
+            n)
+                target=$(d_resolve_network "$num") || { rc=1; continue; }
+                docker_cmd network rm "$target" > /dev/null \
+                    && echo -e "${C_SUCCESS}✓ Removed network $(d_network_name "$target")${C_RESET}" \
+                    || rc=1
+                ;;
+            *)
+                echo -e "${C_ERROR}✗ '${token}' is not a namespaced number (expected c#, i#, v#, or n#)${C_RESET}" >&2
+                rc=1
+                ;;
+        esac
+    done
+    return $rc
+}
+
+# Write $d1..$dN shell variables (bound to container IDs) to a
+# sourceable file, SCM Breeze style, so `docker inspect $d3` works
+# in the calling shell once the wrapper function sources it
+d_write_shell_vars() {
+    local env_file="${D_CACHE_DIR}/env"
+    : > "$env_file"
+
+    local num id rest
+    while IFS=$'\t' read -r num id rest; do
+        echo "export d${num}=${id}" >> "$env_file"
+    done < "$D_CONTAINER_CACHE"
+}
+
+# Print the shell wrapper function users add to their rc file. The
+# wrapper pins D_SHELL_ID and sources the variable file after each
+# run so $d1..$dN stay in sync with the listing.
+# Usage: eval "$(d shellenv)"   (or paste into ~/.bashrc)
+d_shellenv() {
+    cat <<'EOF'
+d() {
+    export D_SHELL_ID=$$
+    command d "$@"
+    local _d_env="/tmp/termflix_docker_$(id -u)/shell_$$/env"
+    [ -f "$_d_env" ] && source "$_d_env"
+}
+EOF
+}
+
+# Print the numbered container listing
+d_list_containers() {
+    if ! d_refresh_containers; then
+        d_listing_error "containers"
+        return 1
+    fi
+
+    d_write_shell_vars
+
+    if [[ "$D_PORCELAIN" == "1" ]]; then
+        # v1: container <num> <id> <name> <image> <status>
+        sed 's/^/container\t/' "$D_CONTAINER_CACHE"
+        return 0
+    fi
+
+    if [[ ! -s "$D_CONTAINER_CACHE" ]]; then
+        echo -e "${C_MUTED}No containers found${C_RESET}"
+        return 0
+    fi
+
+    local num id name image status
+    while IFS=$'\t' read -r num id name image status; do
+        local state_color="$C_MUTED"
+        [[ "$status" == Up* ]] && state_color="$C_SUCCESS"
+        printf "${C_GLOW}[%s]${C_RESET} ${C_CONTRAST}%-24s${C_RESET} ${C_SUBTLE}%-28s${C_RESET} ${state_color}%s${C_RESET}\n" \
+            "$num" "${name:0:24}" "${image:0:28}" "$status"
+    done < "$D_CONTAINER_CACHE"
+}
@@ -0,0 +1,120 @@
+#!/usr/bin/env bash
+#
+# Termflix Docker Favorites Module
+# Pinned containers/images with stable @aliases for `d fav ...`
+#
+
+# Prevent multiple sourcing
+[[ -n "${_TERMFLIX_DOCKER_FAVORITES_LOADED:-}" ]] && return 0
+_TERMFLIX_DOCKER_FAVORITES_LOADED=1
+
+# Favorites live in the termflix config dir so they survive reboots
+# File format (tab-separated): alias, kind (container|image), name.
+# Rows written before the kind column existed have two fields and are
+# read as containers.
+D_FAV_FILE="${HOME}/.config/termflix/docker_favorites"
+
+# ═══════════════════════════════════════════════════════════════
+# FAVORITES STORE
+# ═══════════════════════════════════════════════════════════════
+
+# Look up the name bound to an alias
+# Args: $1 = alias (without the leading @), $2 = kind (default container)
+d_fav_lookup() {
+    [[ -f "$D_FAV_FILE" ]] || return 1
+    awk -F'\t' -v a="$1" -v k="${2:-container}" '
+        $1 == a && ((NF == 2 && k == "container") || (NF >= 3 && $2 == k)) {
+            print $NF; found=1
+        }
+        END { exit !found }' "$D_FAV_FILE"
+}
+
+# Check whether a name is a favorite of the given kind
+# Args: $1 = name, $2 = kind (default container)
+d_fav_has_name() {
+    [[ -f "$D_FAV_FILE" ]] || return 1
+    awk -F'\t' -v n="$1" -v k="${2:-container}" '
+        $NF == n && ((NF == 2 && k == "container") || (NF >= 3 && $2 == k)) {
+            found=1
+        }
+        END { exit !found }' "$D_FAV_FILE"
+}
+
+# Pin a container or image as a favorite
+# Args: $1 = container number, or an i-prefixed image number (i3),
+#       $2 = optional alias (defaults to the name)
+# Usage: d fav add 3 [api]  |  d fav add i2 [base]
+fav_add() {
+    local token="$1" kind="container" name=""
+
+    if [[ "$token" =~ ^i[0-9]+$ ]]; then
+        kind="image"
+        local id
+        id=$(d_resolve_image "${token#i}") || return 1
+        name=$(d_image_name "$id")
+        if [[ -z "$name" || "$name" == "<none>:<none>" ]]; then
+            echo -e "${C_ERROR}✗ Image ${token} has no repo:tag to pin${C_RESET}" >&2
+            return 1
+        fi
+    else
+        [[ "$token" =~ ^c[0-9]+$ ]] && token="${token#c}"
+        local id
+        id=$(d_resolve_container "$token") || return 1
+        name=$(d_container_name "$id")
+    fi
+
+    local alias="${2:-$name}"
+    alias="${alias#@}"
+
+    mkdir -p "$(dirname "$D_FAV_FILE")" 2>/dev/null
+    # Replace an existing binding for the same alias
+    if [[ -f "$D_FAV_FILE" ]]; then
+        grep -v "^${alias}	" "$D_FAV_FILE" > "${D_FAV_FILE}.tmp" 2>/dev/null
+        mv "${D_FAV_FILE}.tmp" "$D_FAV_FILE"
+    fi
+    printf '%s\t%s\t%s\n' "$alias" "$kind" "$name" >> "$D_FAV_FILE"
+
+    echo -e "${C_SUCCESS}✓ Pinned ${name} as @${alias}${C_RESET}"
+}
+
+# Unpin a favorite
+# Args: $1 = alias
+fav_rm() {
+    local alias="${1#@}"
+    if [[ ! -f "$D_FAV_FILE" ]] || ! grep -q "^${alias}	" "$D_FAV_FILE"; then
+        echo -e "${C_ERROR}✗ No favorite named @${alias}${C_RESET}" >&2
+        return 1
+    fi
+    grep -v "^${alias}	" "$D_FAV_FILE" > "${D_FAV_FILE}.tmp"
+    mv "${D_FAV_FILE}.tmp" "$D_FAV_FILE"
+    echo -e "${C_SUCCESS}✓ Removed @${alias}${C_RESET}"
+}
+
+# List favorites with their current state
+fav_ls() {
+    if [[ ! -s "$D_FAV_FILE" ]]; then
+        echo -e "${C_MUTED}No favorites — pin one with 'd fav add <number|i<number>> [alias]'${C_RESET}"
+        return 0
+    fi
+
+    local alias kind name status
+    while IFS=$'\t' read -r alias kind name; do
+        # Legacy two-field rows: the second column is the name
+        if [[ -z "$name" ]]; then
+            name="$kind"
+            kind="container"
+        fi
+
+        if [[ "$kind" == "image" ]]; then
+            status=$(docker_cmd images --format '{{.Size}}' "$name" 2>/dev/null | head -1)
+            [[ -n "$status" ]] && status="image, ${status}"
+        else
+            status=$(docker_cmd ps -a --filter "name=^${name}$" --format '{{.Status}}' | head -1)
+        fi
+
+        local state_color="$C_MUTED"
+        [[ "$status" == Up* || "$status" == image,* ]] && state_color="$C_SUCCESS"
+        printf "${C_GLOW}@%-12s${C_RESET} ${C_CONTRAST}%-24s${C_RESET} ${state_color}%s${C_RESET}\n" \
+            "$alias" "$name" "${status:-not found}"
+    done < "$D_FAV_FILE"
+}
@@ -0,0 +1,797 @@
+#!/usr/bin/env bash
+#
+# Termflix Docker Images Module
+# Image subcommands for `d i ...` operating on numbered selections
+#
+
+# Prevent multiple sourcing
+[[ -n "${_TERMFLIX_DOCKER_IMAGES_LOADED:-}" ]] && return 0
+_TERMFLIX_DOCKER_IMAGES_LOADED=1
+
+# Image cache file (same format idea as the container cache)
+D_IMAGE_CACHE="${D_CACHE_DIR}/images"
+
+# ═══════════════════════════════════════════════════════════════
+# CACHE & LISTING
+# ═══════════════════════════════════════════════════════════════
+
+# Refresh the image cache from docker images
+# Cache format (tab-separated): num, id, repo:tag, size
+d_refresh_images() {
+    init_docker_cache
+
+    local raw rc=0
+    if d_api_available; then
+        raw=$(_d_images_via_api)
+        if [[ -z "$raw" ]]; then
+            raw=$(docker_capture images --format '{{.ID}}\t{{.Repository}}:{{.Tag}}\t{{.Size}}') || rc=$?
+        fi
+    else
+        raw=$(docker_capture images --format '{{.ID}}\t{{.Repository}}:{{.Tag}}\t{{.Size}}') || rc=$?
+    fi
+
+    : > "$D_IMAGE_CACHE"
+    [[ $rc -ne 0 ]] && return 1
+
+    local num=1
+    local line
+    local deferred=()
+
+    # Favorites are numbered first so their positions stay stable
+    while IFS= read -r line; do
+        d_valid_row "$line" 3 || continue
+        local ref
+        ref=$(echo "$line" | cut -f2)
+        if d_fav_has_name "$ref" image 2>/dev/null; then
+            printf '%d\t%s\n' "$num" "$line" >> "$D_IMAGE_CACHE"
+            ((num++))
+        else
+            deferred+=("$line")
+        fi
+    done <<< "$raw"
+
+    for line in "${deferred[@]+"${deferred[@]}"}"; do
+        printf '%d\t%s\n' "$num" "$line" >> "$D_IMAGE_CACHE"
+        ((num++))
+    done
+}
+
+# Resolve a selection token (listing number) to an image ID
+# Args: $1 = token from the command line
+d_resolve_image() {
+    local token="$1"
+
+    [[ -f "$D_IMAGE_CACHE" ]] || d_refresh_images
+
+    # @alias tokens resolve through the favorites store by repo:tag,
+    # so they survive image rebuilds
+    if [[ "$token" == @* ]]; then
+        local fav_ref
+        fav_ref=$(d_fav_lookup "${token#@}" image 2>/dev/null)
+        if [[ -n "$fav_ref" ]]; then
+            local fav_id
+            fav_id=$(docker_cmd images -q "$fav_ref" 2>/dev/null | head -1)
+            if [[ -n "$fav_id" ]]; then
+                echo "$fav_id"
+                return 0
+            fi
+            echo -e "${C_ERROR}✗ Favorite ${token} points at '${fav_ref}' but no such image exists${C_RESET}" >&2
+            return 1
+        fi
+    fi
+
+    if [[ "$token" =~ ^[0-9]+$ ]]; then
+        local id
+        id=$(awk -F'\t' -v n="$token" '$1 == n { print $2 }' "$D_IMAGE_CACHE")
+        if [[ -n "$id" ]]; then
+            echo "$id"
+            return 0
+        fi
+    elif [[ "$token" =~ ^[0-9a-f]{2,64}$ ]]; then
+        # Short image ID prefixes are accepted interchangeably with numbers
+        local id_matches
+        id_matches=$(awk -F'\t' -v t="$token" 'index($2, t) == 1 { print $2 }' "$D_IMAGE_CACHE")
+        if [[ $(echo "$id_matches" | grep -c .) -eq 1 ]]; then
+            echo "$id_matches"
+            return 0
+        fi
+    fi
+
+    echo -e "${C_ERROR}✗ No image matches '${token}' — run 'd i ls' to see the numbered list${C_RESET}" >&2
+    return 1
+}
+
+# Look up the cached repo:tag for an image ID
+# Args: $1 = image ID
+d_image_name() {
+    awk -F'\t' -v id="$1" '$2 == id { print $3 }' "$D_IMAGE_CACHE" 2>/dev/null
+}
+
+# Print the numbered image listing
+d_list_images() {
+    if ! d_refresh_images; then
+        d_listing_error "images"
+        return 1
+    fi
+
+    if [[ "$D_PORCELAIN" == "1" ]]; then
+        # v1: image <num> <id> <repo:tag> <size>
+        sed 's/^/image\t/' "$D_IMAGE_CACHE"
+        return 0
+    fi
+
+    if [[ ! -s "$D_IMAGE_CACHE" ]]; then
+        echo -e "${C_MUTED}No images found${C_RESET}"
+        return 0
+    fi
+
+    local num id repo size
+    while IFS=$'\t' read -r num id repo size; do
+        printf "${C_GLOW}[%s]${C_RESET} ${C_CONTRAST}%-40s${C_RESET} ${C_SUBTLE}%-14s${C_RESET} ${C_MUTED}%s${C_RESET}\n" \
+            "$num" "${repo:0:40}" "$id" "$size"
+    done < "$D_IMAGE_CACHE"
+}
+
+# ═══════════════════════════════════════════════════════════════
+# IMAGE DIFF
+# ═══════════════════════════════════════════════════════════════
+
+# Print one config field of an image via inspect
+# Args: $1 = image ID, $2 = jq-style Go template expression
+_image_config() {
+    docker_cmd inspect --format "$2" "$1" 2>/dev/null
+}
+
+# Diff a single config field between two images
+# Args: $1 = label, $2 = image A ID, $3 = image B ID, $4 = format template
+_image_diff_field() {
+    local label="$1" id_a="$2" id_b="$3" tmpl="$4"
+    local a b
+    a=$(_image_config "$id_a" "$tmpl")
+    b=$(_image_config "$id_b" "$tmpl")
+
+    if [[ "$a" == "$b" ]]; then
+        echo -e "  ${C_MUTED}${label}: unchanged${C_RESET}"
+    else
+        echo -e "  ${C_CONTRAST}${label}:${C_RESET}"
+        echo -e "    ${C_ERROR}- ${a}${C_RESET}"
+        echo -e "    ${C_SUCCESS}+ ${b}${C_RESET}"
+    fi
+}
+
+# Compare two images: config differences and layer-level file diff
+# Args: $1 = first image number, $2 = second image number
+# Usage: d i diff 2 5
+image_diff() {
+    local id_a id_b
+    id_a=$(d_resolve_image "$1") || return 1
+    id_b=$(d_resolve_image "$2") || return 1
+
+    local name_a name_b
+    name_a=$(d_image_name "$id_a")
+    name_b=$(d_image_name "$id_b")
+
+    echo -e "${C_CONTRAST}Comparing ${name_a} → ${name_b}${C_RESET}"
+    echo ""
+
+    echo -e "${C_PURPLE}Config${C_RESET}"
+    _image_diff_field "Env"        "$id_a" "$id_b" '{{range .Config.Env}}{{.}} {{end}}'
+    _image_diff_field "Entrypoint" "$id_a" "$id_b" '{{json .Config.Entrypoint}}'
+    _image_diff_field "Cmd"        "$id_a" "$id_b" '{{json .Config.Cmd}}'
+    _image_diff_field "Ports"      "$id_a" "$id_b" '{{json .Config.ExposedPorts}}'
+    _image_diff_field "Labels"     "$id_a" "$id_b" '{{json .Config.Labels}}'
+    _image_diff_field "WorkingDir" "$id_a" "$id_b" '{{.Config.WorkingDir}}'
+    _image_diff_field "User"       "$id_a" "$id_b" '{{.Config.User}}'
+    echo ""
+
+    echo -e "${C_PURPLE}Layers${C_RESET}"
+    local layers_a layers_b
+    layers_a=$(docker_cmd history --no-trunc --format '{{.CreatedBy}}' "$id_a" | tac)
+    layers_b=$(docker_cmd history --no-trunc --format '{{.CreatedBy}}' "$id_b" | tac)
+
+    diff <(echo "$layers_a") <(echo "$layers_b") \
+        | while IFS= read -r line; do
+            case "$line" in
+                '<'*) echo -e "  ${C_ERROR}- ${line:2:100}${C_RESET}" ;;
+                '>'*) echo -e "  ${C_SUCCESS}+ ${line:2:100}${C_RESET}" ;;
+            esac
+        done
+
+    if [[ "$layers_a" == "$layers_b" ]]; then
+        echo -e "  ${C_MUTED}Layer history identical${C_RESET}"
+    fi
+}
+
+# Readable summary of docker inspect for one image
+# Args: $1 = selection token, optionally --raw for the plain JSON
+# Usage: d i inspect 2 [--raw]
+image_inspect() {
+    local raw=false token=""
+    local arg
+    for arg in "$@"; do
+        case "$arg" in
+            --raw) raw=true ;;
+            *) token="$arg" ;;
+        esac
+    done
+
+    if [[ -z "$token" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d i inspect <number> [--raw]${C_RESET}" >&2
+        return 1
+    fi
+
+    local id
+    id=$(d_resolve_image "$token") || return 1
+
+    local json
+    json=$(docker_capture image inspect "$id") || {
+        echo -e "${C_ERROR}✗ Could not inspect image${C_RESET}" >&2
+        return 1
+    }
+
+    if [[ "$raw" == true ]]; then
+        echo "$json"
+        return 0
+    fi
+
+    echo -e "${C_CONTRAST}$(echo "$json" | jq -r '.[0].RepoTags[0] // "<untagged>"')${C_RESET} ${C_MUTED}(${id:0:12})${C_RESET}"
+    _inspect_line "Created"    "$(echo "$json" | jq -r '.[0].Created | split(".")[0]')"
+    _inspect_line "Size"       "$(echo "$json" | jq -r '.[0].Size / 1048576 | floor | tostring + " MB"')"
+    _inspect_line "Arch"       "$(echo "$json" | jq -r '"\(.[0].Os)/\(.[0].Architecture)"')"
+    _inspect_line "Entrypoint" "$(echo "$json" | jq -r '.[0].Config.Entrypoint // empty | join(" ")')"
+    _inspect_line "Cmd"        "$(echo "$json" | jq -r '.[0].Config.Cmd // empty | join(" ")')"
+    _inspect_line "WorkingDir" "$(echo "$json" | jq -r '.[0].Config.WorkingDir // empty')"
+    _inspect_line "Layers"     "$(echo "$json" | jq -r '.[0].RootFS.Layers | length')"
+}
+
+# Import a container filesystem archive as a new image
+# Args: $1 = tar archive, $2 = repo:tag for the imported image
+# Usage: d import app.tar myapp:snapshot
+image_import() {
+    local archive="${1:-}" ref="${2:-}"
+
+    if [[ -z "$archive" || -z "$ref" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d import <archive.tar> <repo:tag>${C_RESET}" >&2
+        return 1
+    fi
+    if [[ ! -f "$archive" ]]; then
+        echo -e "${C_ERROR}✗ No such file: ${archive}${C_RESET}" >&2
+        return 1
+    fi
+
+    local id
+    id=$(docker_capture import "$archive" "$ref") || {
+        echo -e "${C_ERROR}✗ Import failed${C_RESET}" >&2
+        return 1
+    }
+    echo -e "${C_SUCCESS}✓ Imported ${archive} → ${ref} (${id:7:12})${C_RESET}"
+}
+
+# ═══════════════════════════════════════════════════════════════
+# IMAGE NAMESPACE (d i ...)
+# ═══════════════════════════════════════════════════════════════
+
+# Remove images by number (ranges work like container selections)
+# Args: selection tokens, optionally -f
+# Usage: d i rm 1-3
+image_rm() {
+    local force=""
+    local tokens=()
+    local arg
+    for arg in "$@"; do
+        case "$arg" in
+            -f|--force) force="-f" ;;
+            *) tokens+=("$arg") ;;
+        esac
+    done
+
+    if [[ ${#tokens[@]} -eq 0 ]]; then
+        echo -e "${C_ERROR}✗ Usage: d i rm <numbers...> [-f]${C_RESET}" >&2
+        return 1
+    fi
+
+    local token id rc=0
+    while IFS= read -r token; do
+        id=$(d_resolve_image "$token") || { rc=1; continue; }
+        if docker_cmd rmi $force "$id" > /dev/null; then
+            echo -e "${C_SUCCESS}✓ Removed image $(d_image_name "$id")${C_RESET}"
+        else
+            echo -e "${C_ERROR}✗ Could not remove $(d_image_name "$id")${C_RESET}" >&2
+            rc=1
+        fi
+    done < <(d_expand_selection "${tokens[@]}")
+
+    d_refresh_images
+    return $rc
+}
+
+# Pull image references and refresh the numbered listing. Several
+# references pull concurrently, with one status line per image
+# instead of interleaved layer progress.
+# Args: image references
+# Usage: d i pull nginx:1.27 / d pull nginx redis postgres:16
+image_pull() {
+    if [[ -z "${1:-}" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d i pull <reference...>${C_RESET}" >&2
+        return 1
+    fi
+
+    if [[ $# -eq 1 ]]; then
+        docker_cmd pull "$1" && d_refresh_images
+        return $?
+    fi
+
+    # Parallel pull: one quiet background pull per image, aggregated
+    # completion reporting in submission order
+    local ref pids=() refs=() logs=()
+    for ref in "$@"; do
+        local log="${D_CACHE_DIR}/pull.$$.${#pids[@]}"
+        docker_cmd pull -q "$ref" > "$log" 2>&1 &
+        pids+=($!)
+        refs+=("$ref")
+        logs+=("$log")
+        echo -e "${C_INFO}ℹ Pulling ${ref}...${C_RESET}"
+    done
+
+    local i rc=0
+    for i in "${!pids[@]}"; do
+        if wait "${pids[$i]}"; then
+            echo -e "${C_SUCCESS}✓ Pulled ${refs[$i]}${C_RESET}"
+        else
+            echo -e "${C_ERROR}✗ Pull failed for ${refs[$i]}:${C_RESET}" >&2
+            sed 's/^/    /' "${logs[$i]}" >&2
+            rc=1
+        fi
+        rm -f "${logs[$i]}"
+    done
+
+    d_refresh_images
+    return $rc
+}
+
+# Push a numbered image (or reference) to its registry
+# Args: $1 = selection token or reference
+# Usage: d i push 2
+image_push() {
+    if [[ -z "${1:-}" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d i push <number|reference>${C_RESET}" >&2
+        return 1
+    fi
+
+    local ref="$1"
+    if [[ "$ref" =~ ^[0-9]+$ ]]; then
+        local id
+        id=$(d_resolve_image "$ref") || return 1
+        ref=$(d_image_name "$id")
+        if [[ -z "$ref" || "$ref" == "<none>:<none>" ]]; then
+            echo -e "${C_ERROR}✗ Image ${id:0:12} has no tag to push — tag it first with 'd i tag'${C_RESET}" >&2
+            return 1
+        fi
+    fi
+    docker_cmd push "$ref"
+}
+
+# Tag a numbered image
+# Args: $1 = selection token, $2 = new repo:tag
+# Usage: d i tag 2 myapp:stable
+image_tag() {
+    if [[ -z "${1:-}" || -z "${2:-}" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d i tag <number> <repo:tag>${C_RESET}" >&2
+        return 1
+    fi
+
+    local id
+    id=$(d_resolve_image "$1") || return 1
+    if docker_cmd tag "$id" "$2"; then
+        echo -e "${C_SUCCESS}✓ Tagged ${id:0:12} as $2${C_RESET}"
+        d_refresh_images
+    else
+        return 1
+    fi
+}
+
+# Show the build history of a numbered image
+# Args: $1 = selection token
+# Usage: d i history 1
+image_history() {
+    if [[ -z "${1:-}" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d i history <number>${C_RESET}" >&2
+        return 1
+    fi
+
+    local id
+    id=$(d_resolve_image "$1") || return 1
+    docker_cmd history "$id"
+}
+
+# Save a numbered image to a tar archive
+# Args: $1 = selection token, -o <file>
+# Usage: d i save 2 -o img.tar
+image_save() {
+    local out="" token=""
+    while [[ $# -gt 0 ]]; do
+        case "$1" in
+            -o|--output) out="$2"; shift ;;
+            *)           token="$1" ;;
+        esac
+        shift
+    done
+
+    if [[ -z "$token" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d i save <number> [-o file.tar]${C_RESET}" >&2
+        return 1
+    fi
+
+    local id
+    id=$(d_resolve_image "$token") || return 1
+    if [[ -z "$out" ]]; then
+        out=$(d_image_name "$id" | tr '/:' '__').tar
+        [[ "$out" == ".tar" || "$out" == "_none___none_.tar" ]] && out="${id:0:12}.tar"
+    fi
+
+    if docker_cmd save -o "$out" "$id"; then
+        echo -e "${C_SUCCESS}✓ Saved $(d_image_name "$id") → ${out}${C_RESET}"
+    else
+        return 1
+    fi
+}
+
+# Load images from a tar archive
+# Args: $1 = archive path
+# Usage: d i load img.tar
+image_load() {
+    if [[ ! -f "${1:-}" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d i load <file.tar>${C_RESET}" >&2
+        return 1
+    fi
+    docker_cmd load -i "$1" && d_refresh_images
+}
+
+# ═══════════════════════════════════════════════════════════════
+# DANGLING IMAGES
+# ═══════════════════════════════════════════════════════════════
+
+# Dangling images get their own numbered cache so `d i dangling rm 2`
+# can't collide with the main image numbering
+D_DANGLING_CACHE="${D_CACHE_DIR}/dangling"
+
+# List only dangling/untagged images with numbers and the total size
+# they hold; `rm <numbers|all>` removes selectively
+# Args: optionally rm plus selection tokens or "all"
+# Usage: d i dangling / d i dangling rm 1-5 / d i dangling rm all
+image_dangling() {
+    init_docker_cache
+
+    local raw
+    raw=$(docker_capture images --filter dangling=true --format '{{.ID}}\t{{.Size}}\t{{.CreatedSince}}') || {
+        d_listing_error "images"
+        return 1
+    }
+
+    : > "$D_DANGLING_CACHE"
+    local num=1 line
+    while IFS= read -r line; do
+        d_valid_row "$line" 3 || continue
+        printf '%d\t%s\n' "$num" "$line" >> "$D_DANGLING_CACHE"
+        ((num++))
+    done <<< "$raw"
+
+    if [[ "${1:-}" == "rm" ]]; then
+        shift
+        local tokens=("$@")
+        if [[ "${1:-}" == "all" ]]; then
+            tokens=()
+            local n
+            while IFS=$'\t' read -r n _; do
+                tokens+=("$n")
+            done < "$D_DANGLING_CACHE"
+        fi
+        if [[ ${#tokens[@]} -eq 0 ]]; then
+            echo -e "${C_MUTED}Nothing to remove${C_RESET}"
+            return 0
+        fi
+
+        local token id rc=0
+        while IFS= read -r token; do
+            id=$(awk -F'\t' -v n="$token" '$1 == n { print $2 }' "$D_DANGLING_CACHE")
+            if [[ -z "$id" ]]; then
+                echo -e "${C_ERROR}✗ No dangling image numbered ${token}${C_RESET}" >&2
+                rc=1
+                continue
+            fi
+            if docker_cmd rmi "$id" > /dev/null; then
+                echo -e "${C_SUCCESS}✓ Removed ${id}${C_RESET}"
+            else
+                echo -e "${C_ERROR}✗ Could not remove ${id} (in use?)${C_RESET}" >&2
+                rc=1
+            fi
+        done < <(d_expand_selection "${tokens[@]}")
+        return $rc
+    fi
+
+    if [[ ! -s "$D_DANGLING_CACHE" ]]; then
+        echo -e "${C_MUTED}No dangling images${C_RESET}"
+        return 0
+    fi
+
+    local id size created total_mb=0
+    while IFS=$'\t' read -r num id size created; do
+        printf "${C_GLOW}[%s]${C_RESET} ${C_CONTRAST}%-14s${C_RESET} ${C_SUBTLE}%-10s${C_RESET} ${C_MUTED}%s${C_RESET}\n" \
+            "$num" "$id" "$size" "$created"
+        total_mb=$(( total_mb + $(_size_to_bytes "$size") / 1048576 ))
+    done < "$D_DANGLING_CACHE"
+    echo ""
+    echo -e "${C_INFO}ℹ ~${total_mb} MB reclaimable — remove with 'd i dangling rm <numbers|all>'${C_RESET}"
+}
+
+# ═══════════════════════════════════════════════════════════════
+# LAYER EXPLORER
+# ═══════════════════════════════════════════════════════════════
+
+# Break an image into its layers (base first) showing per-layer size,
+# cumulative size, and the instruction that created it — big layers
+# get flagged so bloat stands out
+# Args: $1 = selection token
+# Usage: d i layers 2
+image_layers() {
+    if [[ -z "${1:-}" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d i layers <number>${C_RESET}" >&2
+        return 1
+    fi
+
+    local id
+    id=$(d_resolve_image "$1") || return 1
+
+    local raw
+    raw=$(docker_capture history --no-trunc --format '{{.Size}}\t{{.CreatedBy}}' "$id") || return 1
+
+    echo -e "${C_CONTRAST}$(d_image_name "$id")${C_RESET} ${C_MUTED}(${id:0:12})${C_RESET}"
+    printf "${C_SUBTLE}%10s %10s  %s${C_RESET}\n" "SIZE" "TOTAL" "CREATED BY"
+
+    # docker history lists newest-first; walk base-first instead
+    local total=0 size created_by
+    while IFS=$'\t' read -r size created_by; do
+        [[ -z "$size" ]] && continue
+        local bytes
+        bytes=$(_size_to_bytes "$size")
+        total=$(( total + bytes ))
+
+        # Strip shell-form noise for readability
+        created_by=$(echo "$created_by" | sed 's|/bin/sh -c #(nop) *||; s|/bin/sh -c |RUN |')
+
+        local size_color="$C_MUTED"
+        [[ $bytes -gt 52428800 ]] && size_color="$C_WARNING"   # > 50 MB
+        [[ $bytes -gt 209715200 ]] && size_color="$C_ERROR"    # > 200 MB
+
+        printf "${size_color}%10s${C_RESET} ${C_SUBTLE}%8s MB${C_RESET}  %s\n" \
+            "$size" "$(( total / 1048576 ))" "${created_by:0:90}"
+    done < <(echo "$raw" | tac)
+}
+
+# ═══════════════════════════════════════════════════════════════
+# VULNERABILITY SCAN
+# ═══════════════════════════════════════════════════════════════
+
+# Scan a numbered image with trivy (preferred) or docker scout,
+# whichever is installed, and summarize findings per severity.
+# --fail-on <severity> exits non-zero when findings at or above that
+# level exist, for CI pipelines.
+# Args: $1 = selection token, optionally --fail-on low|medium|high|critical
+# Usage: d i scan 3 --fail-on high
+image_scan() {
+    local fail_on="" token=""
+    while [[ $# -gt 0 ]]; do
+        case "$1" in
+            --fail-on)   fail_on="$2"; shift ;;
+            --fail-on=*) fail_on="${1#*=}" ;;
+            *)           token="$1" ;;
+        esac
+        shift
+    done
+
+    if [[ -z "$token" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d i scan <number> [--fail-on high]${C_RESET}" >&2
+        return 1
+    fi
+
+    local id
+    id=$(d_resolve_image "$token") || return 1
+    local ref
+    ref=$(d_image_name "$id")
+    [[ -z "$ref" || "$ref" == "<none>:<none>" ]] && ref="$id"
+
+    if command -v trivy &> /dev/null; then
+        echo -e "${C_INFO}ℹ Scanning ${ref} with trivy...${C_RESET}"
+        local json
+        json=$(trivy image --quiet --format json "$ref" 2>/dev/null) || {
+            echo -e "${C_ERROR}✗ trivy scan failed${C_RESET}" >&2
+            return 1
+        }
+
+        local crit high med low
+        crit=$(echo "$json" | jq '[.Results[]?.Vulnerabilities[]? | select(.Severity == "CRITICAL")] | length')
+        high=$(echo "$json" | jq '[.Results[]?.Vulnerabilities[]? | select(.Severity == "HIGH")] | length')
+        med=$(echo "$json" | jq '[.Results[]?.Vulnerabilities[]? | select(.Severity == "MEDIUM")] | length')
+        low=$(echo "$json" | jq '[.Results[]?.Vulnerabilities[]? | select(.Severity == "LOW")] | length')
+
+        printf "${C_SUBTLE}%-10s %s${C_RESET}\n" "SEVERITY" "COUNT"
+        printf "${C_ERROR}%-10s %s${C_RESET}\n"   "critical" "$crit"
+        printf "${C_WARNING}%-10s %s${C_RESET}\n" "high"     "$high"
+        printf "${C_INFO}%-10s %s${C_RESET}\n"    "medium"   "$med"
+        printf "${C_MUTED}%-10s %s${C_RESET}\n"   "low"      "$low"
+
+        if [[ -n "$fail_on" ]]; then
+            local over=0
+            case "$fail_on" in
+                low)      over=$(( crit + high + med + low )) ;;
+                medium)   over=$(( crit + high + med )) ;;
+                high)     over=$(( crit + high )) ;;
+                critical) over=$crit ;;
+                *)
+                    echo -e "${C_ERROR}✗ --fail-on must be low|medium|high|critical${C_RESET}" >&2
+                    return 1
+                    ;;
+            esac
+            if [[ $over -gt 0 ]]; then
+                echo -e "${C_ERROR}✗ ${over} findings at or above '${fail_on}'${C_RESET}" >&2
+                return 1
+            fi
+        fi
+        return 0
+    fi
+
+    if "$DOCKER_BIN" scout version &> /dev/null; then
+        echo -e "${C_INFO}ℹ Scanning ${ref} with docker scout...${C_RESET}"
+        local scout_args=(cves "$ref")
+        if [[ -n "$fail_on" ]]; then
+            scout_args+=(--exit-code --only-severity "${fail_on},critical")
+        fi
+        "$DOCKER_BIN" scout "${scout_args[@]}"
+        return $?
+    fi
+
+    echo -e "${C_ERROR}✗ No scanner found — install trivy or the docker scout plugin${C_RESET}" >&2
+    return 1
+}
+
+# ═══════════════════════════════════════════════════════════════
+# REGISTRY SEARCH
+# ═══════════════════════════════════════════════════════════════
+
+# Search results get their own numbered cache so `d pull 2` can pull
+# straight from the last search
+D_SEARCH_CACHE="${D_CACHE_DIR}/search"
+
+# Search Docker Hub (or the registry the daemon is configured for)
+# and list results with numbers
+# Args: $1 = search term
+# Usage: d search redis
+image_search() {
+    if [[ -z "${1:-}" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d search <term>${C_RESET}" >&2
+        return 1
+    fi
+
+    init_docker_cache
+
+    local raw
+    raw=$(docker_capture search --format '{{.Name}}\t{{.StarCount}}\t{{.IsOfficial}}\t{{.Description}}' \
+        --limit 25 "$1") || {
+        echo -e "${C_ERROR}✗ Search failed — is the registry reachable?${C_RESET}" >&2
+        return 1
+    }
+
+    : > "$D_SEARCH_CACHE"
+    local num=1 line
+    while IFS= read -r line; do
+        [[ -z "$line" ]] && continue
+        printf '%d\t%s\n' "$num" "$line" >> "$D_SEARCH_CACHE"
+        ((num++))
+    done <<< "$raw"
+
+    if [[ ! -s "$D_SEARCH_CACHE" ]]; then
+        echo -e "${C_MUTED}No results for '$1'${C_RESET}"
+        return 0
+    fi
+
+    local name stars official desc
+    while IFS=$'\t' read -r num name stars official desc; do
+        local badge=""
+        [[ "$official" == "true" || "$official" == "[OK]" ]] && badge="${C_SUCCESS}✓${C_RESET} "
+        printf "${C_GLOW}[%s]${C_RESET} ${C_CONTRAST}%-34s${C_RESET} ${C_WARNING}★ %-6s${C_RESET} ${badge}${C_MUTED}%s${C_RESET}\n" \
+            "$num" "${name:0:34}" "$stars" "${desc:0:50}"
+    done < "$D_SEARCH_CACHE"
+    echo ""
+    echo -e "${C_INFO}ℹ Pull a result with 'd pull <number>'${C_RESET}"
+}
+
+# Resolve a token against the last search listing
+# Args: $1 = search result number
+# Output: image name
+d_resolve_search() {
+    local name
+    name=$(awk -F'\t' -v n="$1" '$1 == n { print $2 }' "$D_SEARCH_CACHE" 2>/dev/null)
+    if [[ -z "$name" ]]; then
+        echo -e "${C_ERROR}✗ No search result numbered $1 — run 'd search' first${C_RESET}" >&2
+        return 1
+    fi
+    echo "$name"
+}
+
+# ═══════════════════════════════════════════════════════════════
+# REGISTRY AUTH
+# ═══════════════════════════════════════════════════════════════
+
+# List registries configured as registry.<name>=<url> in docker.conf
+# Output (tab-separated): num, name, url
+_registry_list() {
+    [[ -f "$D_CONF_FILE" ]] || return 0
+    grep '^registry\.[^.=]*[[:space:]]*=' "$D_CONF_FILE" 2>/dev/null \
+        | sed 's/^registry\.//; s/[[:space:]]*=[[:space:]]*/\t/' \
+        | awk -F'\t' '{ printf "%d\t%s\t%s\n", NR, $1, $2 }'
+}
+
+# Log in to a registry. With no argument and configured registries,
+# shows a numbered pick list; a username from registry.<name>.user is
+# passed along so only the password prompt remains.
+# Args: $1 = optional registry number, name, or URL
+# Usage: d login / d login 2 / d login ghcr.io
+registry_login() {
+    local target="${1:-}"
+    local registries
+    registries=$(_registry_list)
+
+    if [[ -z "$target" && -n "$registries" ]]; then
+        echo -e "${C_CONTRAST}Configured registries:${C_RESET}"
+        local num name url
+        while IFS=$'\t' read -r num name url; do
+            printf "${C_GLOW}[%s]${C_RESET} ${C_CONTRAST}%-16s${C_RESET} ${C_MUTED}%s${C_RESET}\n" \
+                "$num" "$name" "$url"
+        done <<< "$registries"
+        echo -en "${C_INFO}Registry number (empty for Docker Hub): ${C_RESET}" >&2
+        read -r target
+    fi
+
+    local url="" cred_name=""
+    if [[ "$target" =~ ^[0-9]+$ ]]; then
+        cred_name=$(echo "$registries" | awk -F'\t' -v n="$target" '$1 == n { print $2 }')
+        url=$(echo "$registries" | awk -F'\t' -v n="$target" '$1 == n { print $3 }')
+        if [[ -z "$url" ]]; then
+            echo -e "${C_ERROR}✗ No registry numbered ${target}${C_RESET}" >&2
+            return 1
+        fi
+    elif [[ -n "$target" ]]; then
+        # Named registry from config, else treat as a literal URL
+        url=$(d_conf_get "registry.${target}" "$target")
+        cred_name="$target"
+    fi
+
+    local login_args=()
+    if [[ -n "$cred_name" ]]; then
+        local user
+        user=$(d_conf_get "registry.${cred_name}.user" "")
+        [[ -n "$user" ]] && login_args+=(--username "$user")
+    fi
+    [[ -n "$url" ]] && login_args+=("$url")
+
+    "$DOCKER_BIN" login "${login_args[@]+"${login_args[@]}"}"
+}
+
+# Log out of a registry (Docker Hub when none given)
+# Args: $1 = optional registry number, name, or URL
+# Usage: d logout / d logout ghcr.io
+registry_logout() {
+    local target="${1:-}"
+
+    if [[ "$target" =~ ^[0-9]+$ ]]; then
+        target=$(_registry_list | awk -F'\t' -v n="$target" '$1 == n { print $3 }')
+    elif [[ -n "$target" ]]; then
+        target=$(d_conf_get "registry.${target}" "$target")
+    fi
+
+    if [[ -n "$target" ]]; then
+        "$DOCKER_BIN" logout "$target"
+    else
+        "$DOCKER_BIN" logout
+    fi
+}
@@ -0,0 +1,160 @@
+#!/usr/bin/env bash
+#
+# Termflix Docker Monitor Module
+# Event watcher with webhook alerts for `d monitor`
+#
+
+# Prevent multiple sourcing
+[[ -n "${_TERMFLIX_DOCKER_MONITOR_LOADED:-}" ]] && return 0
+_TERMFLIX_DOCKER_MONITOR_LOADED=1
+
+# Seconds to suppress repeat alerts for the same container+event
+D_MONITOR_COOLDOWN="${D_MONITOR_COOLDOWN:-300}"
+
+# ═══════════════════════════════════════════════════════════════
+# ALERT DELIVERY
+# ═══════════════════════════════════════════════════════════════
+
+# Post an alert to the configured webhook. Slack/Discord-compatible
+# JSON; plain webhooks get the same payload.
+# Args: $1 = message text
+_monitor_post() {
+    local webhook
+    webhook=$(d_conf_get "monitor.webhook" "")
+    if [[ -z "$webhook" ]]; then
+        return 0
+    fi
+
+    local payload
+    payload=$(jq -n --arg text "$1" '{ text: $text, content: $text }')
+    curl -s -X POST -H 'Content-Type: application/json' -d "$payload" "$webhook" > /dev/null 2>&1
+}
+
+# Alert with dedup: the same container+event within the cool-down
+# window is suppressed
+# Args: $1 = container name, $2 = event, $3 = message
+_monitor_alert() {
+    local name="$1" event="$2" message="$3"
+    local stamp_file="${D_CACHE_DIR}/monitor_${name}_${event}"
+    local now last
+
+    init_docker_cache
+    now=$(date +%s)
+    last=$(cat "$stamp_file" 2>/dev/null || echo 0)
+    if (( now - last < D_MONITOR_COOLDOWN )); then
+        return 0
+    fi
+    echo "$now" > "$stamp_file"
+
+    echo -e "${C_WARNING}⚠ [$(date '+%H:%M:%S')] ${message}${C_RESET}"
+    log_warn "monitor: ${message}"
+    _monitor_post "$message"
+}
+
+# ═══════════════════════════════════════════════════════════════
+# EVENT LOOP
+# ═══════════════════════════════════════════════════════════════
+
+# Watch container events and alert on crashes, OOM kills, and
+# unhealthy transitions
+# Args: --daemon to run in the background (otherwise foreground)
+# Usage: d monitor --daemon
+monitor_run() {
+    if [[ "$1" == "--daemon" ]]; then
+        shift
+        monitor_run "$@" > /dev/null 2>&1 &
+        echo -e "${C_SUCCESS}✓ Monitor running in the background (pid $!)${C_RESET}"
+        return 0
+    fi
+
+    echo -e "${C_INFO}ℹ Watching container events (Ctrl-C to stop)...${C_RESET}"
+    if [[ -z "$(d_conf_get "monitor.webhook" "")" ]]; then
+        echo -e "${C_MUTED}No webhook configured — set monitor.webhook in ${D_CONF_FILE}${C_RESET}"
+    fi
+
+    local line event name exit_code
+    while IFS= read -r line; do
+        event=$(echo "$line" | jq -r '.status // empty' 2>/dev/null)
+        name=$(echo "$line" | jq -r '.Actor.Attributes.name // "unknown"' 2>/dev/null)
+
+        case "$event" in
+            die)
+                exit_code=$(echo "$line" | jq -r '.Actor.Attributes.exitCode // "?"')
+                if [[ "$exit_code" != "0" ]]; then
+                    _monitor_alert "$name" die "Container ${name} died with exit code ${exit_code}"
+                fi
+                ;;
+            oom)
+                _monitor_alert "$name" oom "Container ${name} was OOM-killed"
+                ;;
+            health_status:*unhealthy*)
+                _monitor_alert "$name" unhealthy "Container ${name} became unhealthy"
+                ;;
+        esac
+    done < <(docker_cmd events --filter type=container --format '{{json .}}')
+}
+
+# ═══════════════════════════════════════════════════════════════
+# EVENT STREAM
+# ═══════════════════════════════════════════════════════════════
+
+# Follow the daemon event stream with colorized, human-readable lines
+# Args: --filter k=v (repeatable; container= accepts listing numbers),
+#       --since <duration|timestamp>, anything else passes through
+# Usage: d events --filter container=2 --since 10m
+d_events() {
+    local args=() filter
+    while [[ $# -gt 0 ]]; do
+        case "$1" in
+            --filter)
+                filter=$(_events_filter "$2") || return 1
+                args+=(--filter "$filter")
+                shift
+                ;;
+            --filter=*)
+                filter=$(_events_filter "${1#*=}") || return 1
+                args+=(--filter "$filter")
+                ;;
+            --since|--until)
+                args+=("$1" "$2"); shift ;;
+            *)
+                args+=("$1") ;;
+        esac
+        shift
+    done
+
+    echo -e "${C_INFO}ℹ Streaming events (Ctrl-C to stop)...${C_RESET}"
+
+    local line time type action name
+    while IFS= read -r line; do
+        time=$(echo "$line" | jq -r '.time // empty' 2>/dev/null)
+        type=$(echo "$line" | jq -r '.Type // "?"' 2>/dev/null)
+        action=$(echo "$line" | jq -r '.Action // .status // "?"' 2>/dev/null)
+        name=$(echo "$line" | jq -r '.Actor.Attributes.name // .Actor.ID[0:12] // "?"' 2>/dev/null)
+
+        local color="$C_MUTED"
+        case "$action" in
+            create|start|unpause) color="$C_SUCCESS" ;;
+            die|oom|kill*) color="$C_ERROR" ;;
+            stop|pause|restart) color="$C_WARNING" ;;
+            pull|push|tag|untag) color="$C_INFO" ;;
+        esac
+
+        printf "${C_SUBTLE}%s${C_RESET} ${C_MUTED}%-9s${C_RESET} ${color}%-18s${C_RESET} ${C_CONTRAST}%s${C_RESET}\n" \
+            "$(date -r "$time" '+%H:%M:%S' 2>/dev/null || date -d "@$time" '+%H:%M:%S' 2>/dev/null || echo '--:--:--')" \
+            "$type" "${action:0:18}" "$name"
+    done < <(docker_cmd events --format '{{json .}}' "${args[@]+"${args[@]}"}")
+}
+
+# Expand listing numbers inside a container= filter value
+# Args: $1 = raw filter (k=v)
+_events_filter() {
+    local filter="$1"
+    if [[ "$filter" =~ ^container=[0-9]+$ ]]; then
+        local id
+        id=$(d_resolve_container "${filter#container=}") || return 1
+        echo "container=${id}"
+    else
+        echo "$filter"
+    fi
+}
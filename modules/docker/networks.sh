@@ -0,0 +1,269 @@
+#!/usr/bin/env bash
+#
+# Termflix Docker Networks Module
+# Network subcommands for `d n ...` operating on numbered selections
+#
+
+# Prevent multiple sourcing
+[[ -n "${_TERMFLIX_DOCKER_NETWORKS_LOADED:-}" ]] && return 0
+_TERMFLIX_DOCKER_NETWORKS_LOADED=1
+
+# Network cache file
+D_NETWORK_CACHE="${D_CACHE_DIR}/networks"
+
+# ═══════════════════════════════════════════════════════════════
+# CACHE & LISTING
+# ═══════════════════════════════════════════════════════════════
+
+# Refresh the network cache from docker network ls
+# Cache format (tab-separated): num, id, name, driver
+d_refresh_networks() {
+    init_docker_cache
+
+    local raw rc=0
+    raw=$(docker_capture network ls --format '{{.ID}}\t{{.Name}}\t{{.Driver}}') || rc=$?
+
+    : > "$D_NETWORK_CACHE"
+    [[ $rc -ne 0 ]] && return 1
+
+    local num=1
+    local line
+    while IFS= read -r line; do
+        d_valid_row "$line" 3 || continue
+        printf '%d\t%s\n' "$num" "$line" >> "$D_NETWORK_CACHE"
+        ((num++))
+    done <<< "$raw"
+}
+
+# Resolve a selection token (listing number) to a network ID
+# Args: $1 = token from the command line
+d_resolve_network() {
+    local token="$1"
+
+    [[ -f "$D_NETWORK_CACHE" ]] || d_refresh_networks
+
+    if [[ "$token" =~ ^[0-9]+$ ]]; then
+        local id
+        id=$(awk -F'\t' -v n="$token" '$1 == n { print $2 }' "$D_NETWORK_CACHE")
+        if [[ -n "$id" ]]; then
+            echo "$id"
+            return 0
+        fi
+    fi
+
+    echo -e "${C_ERROR}✗ No network matches '${token}' — run 'd n ls' to see the numbered list${C_RESET}" >&2
+    return 1
+}
+
+# Look up the cached name for a network ID
+# Args: $1 = network ID
+d_network_name() {
+    awk -F'\t' -v id="$1" '$2 == id { print $3 }' "$D_NETWORK_CACHE" 2>/dev/null
+}
+
+# Print the numbered network listing
+d_list_networks() {
+    if ! d_refresh_networks; then
+        d_listing_error "networks"
+        return 1
+    fi
+
+    if [[ "$D_PORCELAIN" == "1" ]]; then
+        # v1: network <num> <id> <name> <driver>
+        sed 's/^/network\t/' "$D_NETWORK_CACHE"
+        return 0
+    fi
+
+    if [[ ! -s "$D_NETWORK_CACHE" ]]; then
+        echo -e "${C_MUTED}No networks found${C_RESET}"
+        return 0
+    fi
+
+    local num id name driver
+    while IFS=$'\t' read -r num id name driver; do
+        printf "${C_GLOW}[%s]${C_RESET} ${C_CONTRAST}%-28s${C_RESET} ${C_SUBTLE}%-14s${C_RESET} ${C_MUTED}%s${C_RESET}\n" \
+            "$num" "${name:0:28}" "$id" "$driver"
+    done < "$D_NETWORK_CACHE"
+}
+
+# Readable summary of docker inspect for one network
+# Args: $1 = selection token, optionally --raw for the plain JSON
+# Usage: d n inspect 2 [--raw]
+network_inspect() {
+    local raw=false token=""
+    local arg
+    for arg in "$@"; do
+        case "$arg" in
+            --raw) raw=true ;;
+            *) token="$arg" ;;
+        esac
+    done
+
+    if [[ -z "$token" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d n inspect <number> [--raw]${C_RESET}" >&2
+        return 1
+    fi
+
+    local id
+    id=$(d_resolve_network "$token") || return 1
+
+    local json
+    json=$(docker_capture network inspect "$id") || {
+        echo -e "${C_ERROR}✗ Could not inspect network${C_RESET}" >&2
+        return 1
+    }
+
+    if [[ "$raw" == true ]]; then
+        echo "$json"
+        return 0
+    fi
+
+    echo -e "${C_CONTRAST}$(echo "$json" | jq -r '.[0].Name')${C_RESET} ${C_MUTED}(${id:0:12})${C_RESET}"
+    _inspect_line "Driver"  "$(echo "$json" | jq -r '.[0].Driver')"
+    _inspect_line "Scope"   "$(echo "$json" | jq -r '.[0].Scope')"
+    _inspect_line "Subnet"  "$(echo "$json" | jq -r '[.[0].IPAM.Config[]?.Subnet] | join(", ")')"
+    _inspect_line "Gateway" "$(echo "$json" | jq -r '[.[0].IPAM.Config[]?.Gateway // empty] | join(", ")')"
+
+    local attached
+    attached=$(echo "$json" | jq -r '.[0].Containers | to_entries[]? | "\(.value.Name) (\(.value.IPv4Address))"')
+    if [[ -n "$attached" ]]; then
+        echo -e "  ${C_SUBTLE}Containers${C_RESET}"
+        echo "$attached" | sed "s/^/    /"
+    fi
+}
+
+# ═══════════════════════════════════════════════════════════════
+# CREATE / CONNECT
+# ═══════════════════════════════════════════════════════════════
+
+# Create a network via flags or interactive prompts, then re-list
+# Args: optionally name, --driver, --subnet, --gateway, --attachable,
+#       --internal, --label k=v (repeatable)
+# Usage: d n create / d n create mynet --driver bridge --subnet 172.30.0.0/16
+network_create() {
+    local name="" driver="" subnet="" gateway=""
+    local flags=() labels=()
+
+    while [[ $# -gt 0 ]]; do
+        case "$1" in
+            --driver)     driver="$2"; shift ;;
+            --subnet)     subnet="$2"; shift ;;
+            --gateway)    gateway="$2"; shift ;;
+            --attachable) flags+=(--attachable) ;;
+            --internal)   flags+=(--internal) ;;
+            --label)      labels+=(--label "$2"); shift ;;
+            *)            name="$1" ;;
+        esac
+        shift
+    done
+
+    if [[ -z "$name" ]]; then
+        name=$(_prompt_default "Network name" "")
+        if [[ -z "$name" ]]; then
+            echo -e "${C_ERROR}✗ A network needs a name${C_RESET}" >&2
+            return 1
+        fi
+        driver=$(_prompt_default "Driver" "bridge")
+        subnet=$(_prompt_default "Subnet (empty for auto)" "")
+        [[ -n "$subnet" ]] && gateway=$(_prompt_default "Gateway (empty for auto)" "")
+        local yn
+        yn=$(_prompt_default "Attachable? (y/n)" "n")
+        [[ "$yn" == "y" ]] && flags+=(--attachable)
+        yn=$(_prompt_default "Internal (no outbound)? (y/n)" "n")
+        [[ "$yn" == "y" ]] && flags+=(--internal)
+    fi
+
+    local create_args=()
+    [[ -n "$driver" ]] && create_args+=(--driver "$driver")
+    [[ -n "$subnet" ]] && create_args+=(--subnet "$subnet")
+    [[ -n "$gateway" ]] && create_args+=(--gateway "$gateway")
+    create_args+=("${flags[@]+"${flags[@]}"}" "${labels[@]+"${labels[@]}"}" "$name")
+
+    if docker_cmd network create "${create_args[@]}" > /dev/null; then
+        echo -e "${C_SUCCESS}✓ Created network ${name}${C_RESET}"
+        echo ""
+        d_list_networks
+    else
+        echo -e "${C_ERROR}✗ Could not create network ${name}${C_RESET}" >&2
+        return 1
+    fi
+}
+
+# Connect or disconnect containers and networks by their listing
+# numbers (tokens also accept names/@aliases via the usual resolvers)
+# Args: $1 = connect|disconnect, $2 = network token, $3 = container token
+# Usage: d n connect 2 1 / d n disconnect 2 @web
+_network_wire() {
+    local verb="$1" net_token="${2:-}" cont_token="${3:-}"
+
+    if [[ -z "$net_token" || -z "$cont_token" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d n ${verb} <network#> <container#>${C_RESET}" >&2
+        return 1
+    fi
+
+    local net_id cont_id
+    net_id=$(d_resolve_network "$net_token") || return 1
+    cont_id=$(d_resolve_container "$cont_token") || return 1
+
+    local net_name
+    net_name=$(d_network_name "$net_id")
+    if docker_cmd network "$verb" "$net_id" "$cont_id"; then
+        echo -e "${C_SUCCESS}✓ ${verb}ed $(d_container_name "$cont_id") ↔ ${net_name}${C_RESET}"
+    else
+        echo -e "${C_ERROR}✗ Could not ${verb} $(d_container_name "$cont_id") and ${net_name}${C_RESET}" >&2
+        return 1
+    fi
+}
+
+network_connect()    { _network_wire connect "$@"; }
+network_disconnect() { _network_wire disconnect "$@"; }
+
+# ═══════════════════════════════════════════════════════════════
+# TOPOLOGY MAP
+# ═══════════════════════════════════════════════════════════════
+
+# ASCII map of every network and what hangs off it: attached
+# containers with their IPs and published ports
+# Usage: d n map
+network_map() {
+    if ! d_refresh_networks; then
+        d_listing_error "networks"
+        return 1
+    fi
+
+    local _num id name driver
+    while IFS=$'\t' read -r _num id name driver; do
+        local json
+        json=$(docker_capture network inspect "$id" 2>/dev/null) || continue
+
+        local subnet
+        subnet=$(echo "$json" | jq -r '[.[0].IPAM.Config[]?.Subnet] | join(", ")')
+        echo -e "${C_PURPLE}${name}${C_RESET} ${C_SUBTLE}(${driver}${subnet:+, ${subnet}})${C_RESET}"
+
+        local members
+        members=$(echo "$json" | jq -r '.[0].Containers | to_entries[]? | "\(.value.Name)\t\(.value.IPv4Address | split("/")[0])"')
+        if [[ -z "$members" ]]; then
+            echo -e "  ${C_MUTED}(no containers attached)${C_RESET}"
+            echo ""
+            continue
+        fi
+
+        local total
+        total=$(echo "$members" | grep -c .)
+        local i=0 cname ip
+        while IFS=$'\t' read -r cname ip; do
+            ((i++))
+            local branch="├─"
+            [[ $i -eq $total ]] && branch="└─"
+
+            # Published ports, if the container maps any
+            local ports
+            ports=$(docker_cmd port "$cname" 2>/dev/null \
+                | awk '{ printf ":%s→%s ", substr($3, index($3, ":") + 1), $1 }')
+
+            printf " ${C_SUBTLE}%s${C_RESET} ${C_CONTRAST}%-24s${C_RESET} ${C_GLOW}%-16s${C_RESET} ${C_MUTED}%s${C_RESET}\n" \
+                "$branch" "${cname:0:24}" "$ip" "$ports"
+        done <<< "$members"
+        echo ""
+    done < "$D_NETWORK_CACHE"
+}
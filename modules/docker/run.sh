@@ -0,0 +1,105 @@
+#!/usr/bin/env bash
+#
+# Termflix Docker Run Module
+# Guided `docker run` builder for `d run`
+#
+
+# Prevent multiple sourcing
+[[ -n "${_TERMFLIX_DOCKER_RUN_LOADED:-}" ]] && return 0
+_TERMFLIX_DOCKER_RUN_LOADED=1
+
+# ═══════════════════════════════════════════════════════════════
+# RUN WIZARD
+# ═══════════════════════════════════════════════════════════════
+
+# Pick an image for the run: a passed token resolves through the
+# numbered image listing, otherwise drop into fzf (or a prompt)
+# Args: $1 = optional selection token or image reference
+# Output: image reference
+_run_pick_image() {
+    local token="${1:-}"
+
+    if [[ -n "$token" ]]; then
+        if [[ "$token" =~ ^[0-9]+$ ]]; then
+            d_resolve_image "$token"
+            return $?
+        fi
+        echo "$token"
+        return 0
+    fi
+
+    local refs
+    refs=$(docker_capture images --format '{{.Repository}}:{{.Tag}}' | grep -v '^<none>')
+
+    if command -v fzf &> /dev/null && [[ -n "$refs" ]]; then
+        echo "$refs" | fzf --prompt="image > " --height=50% --reverse
+    else
+        _prompt_default "Image" "alpine:latest"
+    fi
+}
+
+# Collect repeated values until an empty answer
+# Args: $1 = prompt text (e.g. "Port mapping host:container")
+# Output: one value per line
+_run_collect() {
+    local value
+    while true; do
+        echo -en "${C_INFO}$1 (empty to finish): ${C_RESET}" >&2
+        read -r value
+        [[ -z "$value" ]] && break
+        echo "$value"
+    done
+}
+
+# Guided docker run: pick an image, then build up ports, volumes,
+# env vars, name, and restart policy interactively; preview the full
+# command line before executing it
+# Args: $1 = optional image number or reference
+# Usage: d run [image]
+container_run_wizard() {
+    local image
+    image=$(_run_pick_image "${1:-}") || return 1
+    if [[ -z "$image" ]]; then
+        echo -e "${C_ERROR}✗ No image selected${C_RESET}" >&2
+        return 1
+    fi
+
+    local run_args=()
+
+    local name
+    name=$(_prompt_default "Container name" "")
+    [[ -n "$name" ]] && run_args+=(--name "$name")
+
+    local value
+    while IFS= read -r value; do
+        run_args+=(-p "$value")
+    done < <(_run_collect "Port mapping host:container")
+
+    while IFS= read -r value; do
+        run_args+=(-v "$value")
+    done < <(_run_collect "Volume host:container")
+
+    while IFS= read -r value; do
+        run_args+=(-e "$value")
+    done < <(_run_collect "Env KEY=VAL")
+
+    local restart
+    restart=$(_prompt_default "Restart policy (no|on-failure|unless-stopped|always)" "no")
+    [[ "$restart" != "no" ]] && run_args+=(--restart "$restart")
+
+    local mode
+    mode=$(_prompt_default "Mode (d=detached, it=interactive)" "d")
+    if [[ "$mode" == "it" ]]; then
+        run_args+=(-it)
+    else
+        run_args+=(-d)
+    fi
+
+    echo ""
+    echo -e "${C_CONTRAST}docker run ${run_args[*]} ${image}${C_RESET}"
+    local confirm
+    confirm=$(_prompt_default "Run it?" "y")
+    [[ "$confirm" != "y" ]] && return 0
+
+    docker_cmd run "${run_args[@]}" "$image" && d_refresh_containers
+}
@@ -0,0 +1,160 @@
+#!/usr/bin/env bash
+#
+# Termflix Docker Scaffold Module
+# Dockerfile generation wizard for `d init`
+#
+
+# Prevent multiple sourcing
+[[ -n "${_TERMFLIX_DOCKER_SCAFFOLD_LOADED:-}" ]] && return 0
+_TERMFLIX_DOCKER_SCAFFOLD_LOADED=1
+
+# ═══════════════════════════════════════════════════════════════
+# LANGUAGE DETECTION
+# ═══════════════════════════════════════════════════════════════
+
+# Detect the project language from well-known manifest files
+# Output: go | node | python | unknown
+_detect_project_language() {
+    if [[ -f "go.mod" ]]; then
+        echo "go"
+    elif [[ -f "package.json" ]]; then
+        echo "node"
+    elif [[ -f "requirements.txt" || -f "pyproject.toml" ]]; then
+        echo "python"
+    else
+        echo "unknown"
+    fi
+}
+
+# Prompt with a default value
+# Args: $1 = prompt text, $2 = default
+_prompt_default() {
+    local answer
+    echo -en "${C_INFO}$1 [$2]: ${C_RESET}" >&2
+    read -r answer
+    echo "${answer:-$2}"
+}
+
+# ═══════════════════════════════════════════════════════════════
+# DOCKERFILE TEMPLATES
+# ═══════════════════════════════════════════════════════════════
+
+# Write a multi-stage Dockerfile for a Go project
+# Args: $1 = base image, $2 = port
+_scaffold_go() {
+    cat > Dockerfile <<EOF
+# Build stage
+FROM ${1} AS build
+WORKDIR /src
+COPY go.mod go.sum ./
+RUN go mod download
+COPY . .
+RUN CGO_ENABLED=0 go build -o /app .
+
+# Runtime stage
+FROM gcr.io/distroless/static-debian12
+COPY --from=build /app /app
+EXPOSE ${2}
+ENTRYPOINT ["/app"]
+EOF
+}
+
+# Write a multi-stage Dockerfile for a Node project
+# Args: $1 = base image, $2 = port
+_scaffold_node() {
+    cat > Dockerfile <<EOF
+# Build stage
+FROM ${1} AS build
+WORKDIR /app
+COPY package*.json ./
+RUN npm ci
+COPY . .
+RUN npm run build --if-present
+
+# Runtime stage
+FROM ${1}
+WORKDIR /app
+ENV NODE_ENV=production
+COPY --from=build /app ./
+EXPOSE ${2}
+CMD ["npm", "start"]
+EOF
+}
+
+# Write a multi-stage Dockerfile for a Python project
+# Args: $1 = base image, $2 = port
+_scaffold_python() {
+    cat > Dockerfile <<EOF
+# Build stage
+FROM ${1} AS build
+WORKDIR /app
+COPY requirements.txt .
+RUN pip install --prefix=/install --no-cache-dir -r requirements.txt
+
+# Runtime stage
+FROM ${1}
+WORKDIR /app
+COPY --from=build /install /usr/local
+COPY . .
+EXPOSE ${2}
+CMD ["python", "main.py"]
+EOF
+}
+
+# Write a .dockerignore appropriate for the detected language
+# Args: $1 = language
+_scaffold_dockerignore() {
+    {
+        echo ".git"
+        echo "Dockerfile"
+        echo ".dockerignore"
+        case "$1" in
+            go)     echo "*.test"; echo "vendor/" ;;
+            node)   echo "node_modules/"; echo "npm-debug.log" ;;
+            python) echo "__pycache__/"; echo "*.pyc"; echo ".venv/" ;;
+        esac
+    } > .dockerignore
+}
+
+# ═══════════════════════════════════════════════════════════════
+# WIZARD
+# ═══════════════════════════════════════════════════════════════
+
+# Dockerfile scaffolding wizard: detect language, prompt for port
+# and base image, and generate Dockerfile + .dockerignore
+# Usage: d init
+project_init() {
+    if [[ -f "Dockerfile" ]]; then
+        echo -e "${C_WARNING}⚠ Dockerfile already exists${C_RESET}"
+        local overwrite
+        overwrite=$(_prompt_default "Overwrite?" "n")
+        [[ "$overwrite" != "y" ]] && return 1
+    fi
+
+    local lang
+    lang=$(_detect_project_language)
+    if [[ "$lang" == "unknown" ]]; then
+        echo -e "${C_ERROR}✗ Could not detect project language (no go.mod, package.json, or requirements.txt)${C_RESET}" >&2
+        return 1
+    fi
+    echo -e "${C_INFO}ℹ Detected ${lang} project${C_RESET}"
+
+    local default_base port base
+    case "$lang" in
+        go)     default_base="golang:1.23-alpine" ;;
+        node)   default_base="node:22-alpine" ;;
+        python) default_base="python:3.12-slim" ;;
+    esac
+
+    base=$(_prompt_default "Base image" "$default_base")
+    port=$(_prompt_default "Application port" "8080")
+
+    case "$lang" in
+        go)     _scaffold_go "$base" "$port" ;;
+        node)   _scaffold_node "$base" "$port" ;;
+        python) _scaffold_python "$base" "$port" ;;
+    esac
+    _scaffold_dockerignore "$lang"
+
+    echo -e "${C_SUCCESS}✓ Wrote Dockerfile and .dockerignore${C_RESET}"
+}
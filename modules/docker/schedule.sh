@@ -0,0 +1,136 @@
+#!/usr/bin/env bash
+#
+# Termflix Docker Schedule Module
+# Cron-like scheduler for routine maintenance via `d schedule ...`
+#
+
+# Prevent multiple sourcing
+[[ -n "${_TERMFLIX_DOCKER_SCHEDULE_LOADED:-}" ]] && return 0
+_TERMFLIX_DOCKER_SCHEDULE_LOADED=1
+
+# Schedule entries (tab-separated): cron expression, d command words
+D_SCHEDULE_FILE="${HOME}/.config/termflix/docker_schedule"
+
+# ═══════════════════════════════════════════════════════════════
+# CRON MATCHING
+# ═══════════════════════════════════════════════════════════════
+
+# Match one cron field against a value. Supports *, */n, comma
+# lists, and plain numbers.
+# Args: $1 = field, $2 = current value
+_cron_field_match() {
+    local field="$1" value="$2"
+    local part
+
+    for part in ${field//,/ }; do
+        case "$part" in
+            \*) return 0 ;;
+            \*/[0-9]*)
+                local step="${part#\*/}"
+                [[ $(( 10#$value % step )) -eq 0 ]] && return 0
+                ;;
+            *)
+                [[ $(( 10#$part )) -eq $(( 10#$value )) ]] && return 0
+                ;;
+        esac
+    done
+    return 1
+}
+
+# Check whether a 5-field cron expression matches the current minute
+# Args: $1 = cron expression
+_cron_matches_now() {
+    local expr="$1"
+    local min hour dom mon dow
+    read -r min hour dom mon dow <<< "$expr"
+
+    _cron_field_match "$min"  "$(date +%M)" || return 1
+    _cron_field_match "$hour" "$(date +%H)" || return 1
+    _cron_field_match "$dom"  "$(date +%d)" || return 1
+    _cron_field_match "$mon"  "$(date +%m)" || return 1
+    _cron_field_match "$dow"  "$(date +%w)" || return 1
+    return 0
+}
+
+# ═══════════════════════════════════════════════════════════════
+# SCHEDULE MANAGEMENT
+# ═══════════════════════════════════════════════════════════════
+
+# Add a scheduled task
+# Args: $1 = cron expression, rest = d command words
+# Usage: d schedule add "0 3 * * *" prune images
+schedule_add() {
+    local expr="$1"
+    shift
+
+    if [[ -z "$expr" || $# -eq 0 ]]; then
+        echo -e "${C_ERROR}✗ Usage: d schedule add \"<cron expr>\" <d command...>${C_RESET}" >&2
+        return 1
+    fi
+    if [[ $(echo "$expr" | wc -w) -ne 5 ]]; then
+        echo -e "${C_ERROR}✗ Cron expression needs 5 fields (min hour dom mon dow)${C_RESET}" >&2
+        return 1
+    fi
+
+    mkdir -p "$(dirname "$D_SCHEDULE_FILE")" 2>/dev/null
+    printf '%s\t%s\n' "$expr" "$*" >> "$D_SCHEDULE_FILE"
+    echo -e "${C_SUCCESS}✓ Scheduled: [${expr}] d $*${C_RESET}"
+}
+
+# List scheduled tasks with numbers
+schedule_ls() {
+    if [[ ! -s "$D_SCHEDULE_FILE" ]]; then
+        echo -e "${C_MUTED}No scheduled tasks${C_RESET}"
+        return 0
+    fi
+
+    local num=1 expr cmd
+    while IFS=$'\t' read -r expr cmd; do
+        printf "${C_GLOW}[%d]${C_RESET} ${C_SUBTLE}%-16s${C_RESET} ${C_CONTRAST}d %s${C_RESET}\n" \
+            "$num" "$expr" "$cmd"
+        ((num++))
+    done < "$D_SCHEDULE_FILE"
+}
+
+# Remove a scheduled task by number
+# Args: $1 = task number from `d schedule ls`
+schedule_rm() {
+    local num="$1"
+    if [[ ! "$num" =~ ^[0-9]+$ ]] || [[ ! -s "$D_SCHEDULE_FILE" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d schedule rm <number>${C_RESET}" >&2
+        return 1
+    fi
+    sed "${num}d" "$D_SCHEDULE_FILE" > "${D_SCHEDULE_FILE}.tmp"
+    mv "${D_SCHEDULE_FILE}.tmp" "$D_SCHEDULE_FILE"
+    echo -e "${C_SUCCESS}✓ Removed task ${num}${C_RESET}"
+}
+
+# Run the scheduler: check entries once per minute and run matches
+# Args: --daemon to keep running (otherwise a single pass)
+# Usage: d schedule run --daemon
+schedule_run() {
+    local daemon=false
+    [[ "$1" == "--daemon" ]] && daemon=true
+
+    if [[ ! -s "$D_SCHEDULE_FILE" ]]; then
+        echo -e "${C_MUTED}No scheduled tasks — add one with 'd schedule add'${C_RESET}"
+        return 0
+    fi
+
+    while true; do
+        local expr cmd
+        while IFS=$'\t' read -r expr cmd; do
+            [[ -z "$expr" ]] && continue
+            if _cron_matches_now "$expr"; then
+                log_info "schedule: running 'd ${cmd}'"
+                echo -e "${C_INFO}ℹ [$(date '+%H:%M')] Running: d ${cmd}${C_RESET}"
+                # shellcheck disable=SC2086
+                "$SCRIPT_DIR/d" $cmd
+            fi
+        done < "$D_SCHEDULE_FILE"
+
+        [[ "$daemon" != true ]] && break
+        # Sleep to the start of the next minute
+        sleep $(( 60 - 10#$(date +%S) ))
+    done
+}
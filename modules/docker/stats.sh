@@ -0,0 +1,417 @@
+#!/usr/bin/env bash
+#
+# Termflix Docker Stats Module
+# Container stats display and metric shipping for `d stats`
+#
+
+# Prevent multiple sourcing
+[[ -n "${_TERMFLIX_DOCKER_STATS_LOADED:-}" ]] && return 0
+_TERMFLIX_DOCKER_STATS_LOADED=1
+
+# ═══════════════════════════════════════════════════════════════
+# STATS SNAPSHOT
+# ═══════════════════════════════════════════════════════════════
+
+# One stats sample for all running containers
+# Output (tab-separated): name, cpu%, mem bytes, net rx/tx, block io
+_stats_sample() {
+    docker_cmd stats --no-stream --format \
+        '{{.Name}}\t{{.CPUPerc}}\t{{.MemUsage}}\t{{.NetIO}}\t{{.BlockIO}}'
+}
+
+# Order a sample by cpu, mem, or net (descending)
+# Args: $1 = sort key, stdin = sample lines
+_stats_sort() {
+    case "$1" in
+        cpu)
+            awk -F'\t' '{ k = $2; sub(/%/, "", k); printf "%s\t%s\n", k, $0 }' \
+                | sort -t$'\t' -k1,1nr | cut -f2-
+            ;;
+        mem)
+            awk -F'\t' '
+                {
+                    # Usage only — the "/ LIMIT" part would match too
+                    k = $3
+                    sub(/ \/.*/, "", k)
+                    mult = 1
+                    if (k ~ /^[0-9.]+GiB/) mult = 1024
+                    printf "%s\t%s\n", (k + 0) * mult, $0
+                }' \
+                | sort -t$'\t' -k1,1nr | cut -f2-
+            ;;
+        net)
+            awk -F'\t' '
+                {
+                    k = $4
+                    mult = 1
+                    if (k ~ /^[0-9.]+MB/) mult = 1024
+                    if (k ~ /^[0-9.]+GB/) mult = 1048576
+                    printf "%s\t%s\n", (k + 0) * mult, $0
+                }' \
+                | sort -t$'\t' -k1,1nr | cut -f2-
+            ;;
+        *)
+            cat
+            ;;
+    esac
+}
+
+# Print one stats row, highlighting threshold breaches
+# (stats.cpu.warn / stats.mem.warn in the config, percent, default 80)
+_stats_row() {
+    local name="$1" cpu="$2" mem="$3" net="$4" block="$5"
+    local cpu_warn mem_warn
+    cpu_warn=$(d_conf_get "stats.cpu.warn" "80")
+    mem_warn=$(d_conf_get "stats.mem.warn" "80")
+
+    local cpu_color="$C_GLOW" mem_color="$C_SUBTLE"
+    if awk -v a="${cpu%\%}" -v b="$cpu_warn" 'BEGIN { exit !(a >= b) }'; then
+        cpu_color="$C_ERROR"
+    fi
+    local used limit
+    used=$(_size_to_bytes "${mem%% /*}")
+    limit=$(_size_to_bytes "${mem##* }")
+    if [[ "${limit:-0}" -gt 0 ]] && [[ $(( used * 100 / limit )) -ge "$mem_warn" ]]; then
+        mem_color="$C_ERROR"
+    fi
+
+    printf "${C_CONTRAST}%-24s${C_RESET} ${cpu_color}%8s${C_RESET} ${mem_color}%-20s${C_RESET} ${C_MUTED}%-18s %s${C_RESET}\n" \
+        "${name:0:24}" "$cpu" "$mem" "$net" "$block"
+}
+
+# Show a stats table, one-shot by default
+# Args: --watch to refresh in place, --sort cpu|mem|net
+# Usage: d stats --watch --sort cpu
+d_stats() {
+    local watch=0 sort_key=""
+    while [[ $# -gt 0 ]]; do
+        case "$1" in
+            --watch|-w) watch=1 ;;
+            --sort) sort_key="$2"; shift ;;
+            --sort=*) sort_key="${1#*=}" ;;
+            --by-project) d_stats_by_project; return $? ;;
+            *)
+                echo -e "${C_ERROR}✗ Usage: d stats [--watch] [--sort cpu|mem|net]${C_RESET}" >&2
+                return 1
+                ;;
+        esac
+        shift
+    done
+    if [[ -n "$sort_key" && ! "$sort_key" =~ ^(cpu|mem|net)$ ]]; then
+        echo -e "${C_ERROR}✗ --sort takes cpu, mem, or net${C_RESET}" >&2
+        return 1
+    fi
+
+    while true; do
+        local sample
+        sample=$(_stats_sample | _stats_sort "$sort_key")
+
+        [[ $watch -eq 1 ]] && printf '\033[H\033[2J'
+        printf "${C_SUBTLE}%-24s %8s %-20s %-18s %s${C_RESET}\n" \
+            "CONTAINER" "CPU" "MEM" "NET I/O" "BLOCK I/O"
+        local name cpu mem net block
+        while IFS=$'\t' read -r name cpu mem net block; do
+            [[ -z "$name" ]] && continue
+            _stats_row "$name" "$cpu" "$mem" "$net" "$block"
+        done <<< "$sample"
+
+        [[ $watch -eq 1 ]] || break
+        sleep 2
+    done
+}
+
+# Stream live stats for selected numbered containers only,
+# refreshing in place until Ctrl-C
+# Args: selection tokens (numbers, ranges, @aliases)
+# Usage: d c stats 1 4
+container_stats() {
+    if [[ $# -eq 0 ]]; then
+        echo -e "${C_ERROR}✗ Usage: d c stats <numbers...>${C_RESET}" >&2
+        return 1
+    fi
+
+    local ids=() token id
+    while IFS= read -r token; do
+        id=$(d_resolve_container "$token") || return 1
+        ids+=("$id")
+    done < <(d_expand_selection "$@")
+
+    docker_cmd stats "${ids[@]}"
+}
+
+# Aggregate CPU and memory per compose project so heavy stacks stand
+# out instead of their individual containers
+# Usage: d stats --by-project
+d_stats_by_project() {
+    local agg_file="${D_CACHE_DIR}/stats_project.$$"
+    init_docker_cache
+    : > "$agg_file"
+
+    local name cpu mem _net _block
+    while IFS=$'\t' read -r name cpu mem _net _block; do
+        [[ -z "$name" ]] && continue
+        local project
+        project=$(_container_project "$name")
+        printf '%s\t%s\t%s\n' "$project" "${cpu%\%}" \
+            "$(( $(_size_to_bytes "${mem%% /*}") / 1048576 ))" >> "$agg_file"
+    done < <(_stats_sample)
+
+    if [[ ! -s "$agg_file" ]]; then
+        echo -e "${C_MUTED}No running containers${C_RESET}"
+        rm -f "$agg_file"
+        return 0
+    fi
+
+    printf "${C_SUBTLE}%-24s %12s %10s %12s${C_RESET}\n" \
+        "PROJECT" "CONTAINERS" "CPU%" "MEM(MB)"
+    awk -F'\t' '
+        {
+            count[$1]++
+            cpu[$1] += $2
+            mem[$1] += $3
+        }
+        END {
+            for (p in count)
+                printf "%s\t%d\t%.1f\t%d\n", p, count[p], cpu[p], mem[p]
+        }' "$agg_file" \
+        | sort -t$'\t' -k3,3nr \
+        | while IFS=$'\t' read -r project count cpu_sum mem_sum; do
+            local label="$project"
+            [[ "$label" == "-" ]] && label="(standalone)"
+            printf "${C_CONTRAST}%-24s${C_RESET} ${C_MUTED}%12s${C_RESET} ${C_GLOW}%10s${C_RESET} ${C_SUBTLE}%12s${C_RESET}\n" \
+                "${label:0:24}" "$count" "$cpu_sum" "$mem_sum"
+        done
+
+    rm -f "$agg_file"
+}
+
+# ═══════════════════════════════════════════════════════════════
+# PROJECT QUOTAS
+# ═══════════════════════════════════════════════════════════════
+
+# Map a container name to its compose project (or "-" if standalone)
+# Args: $1 = container name
+_container_project() {
+    docker_cmd inspect --format \
+        '{{index .Config.Labels "com.docker.compose.project"}}' "$1" 2>/dev/null \
+        | sed 's/^$/-/'
+}
+
+# Aggregate CPU and memory per compose project and compare against
+# soft limits from the config (quota.<project>.cpu / quota.<project>.mem_mb)
+# Usage: d quota
+d_quota() {
+    local agg_file="${D_CACHE_DIR}/quota.$$"
+    init_docker_cache
+    : > "$agg_file"
+
+    local name cpu mem _net _block
+    while IFS=$'\t' read -r name cpu mem _net _block; do
+        local project cpu_val mem_mb
+        project=$(_container_project "$name")
+        cpu_val="${cpu%\%}"
+        mem_mb=$(( $(_size_to_bytes "${mem%% /*}") / 1048576 ))
+        printf '%s\t%s\t%s\n' "$project" "$cpu_val" "$mem_mb" >> "$agg_file"
+    done < <(_stats_sample)
+
+    if [[ ! -s "$agg_file" ]]; then
+        echo -e "${C_MUTED}No running containers${C_RESET}"
+        rm -f "$agg_file"
+        return 0
+    fi
+
+    printf "${C_SUBTLE}%-24s %10s %12s %10s${C_RESET}\n" "PROJECT" "CPU%" "MEM(MB)" "BUDGET"
+    local project
+    while IFS= read -r project; do
+        local cpu_sum mem_sum
+        cpu_sum=$(awk -F'\t' -v p="$project" '$1 == p { s += $2 } END { printf "%.1f", s }' "$agg_file")
+        mem_sum=$(awk -F'\t' -v p="$project" '$1 == p { s += $3 } END { printf "%d", s }' "$agg_file")
+
+        local cpu_limit mem_limit over=""
+        cpu_limit=$(d_conf_get "quota.${project}.cpu" "")
+        mem_limit=$(d_conf_get "quota.${project}.mem_mb" "")
+        if [[ -n "$cpu_limit" ]] && awk -v a="$cpu_sum" -v b="$cpu_limit" 'BEGIN { exit !(a > b) }'; then
+            over="cpu>${cpu_limit}%"
+        fi
+        if [[ -n "$mem_limit" ]] && [[ "$mem_sum" -gt "$mem_limit" ]]; then
+            over="${over:+${over} }mem>${mem_limit}MB"
+        fi
+
+        if [[ -n "$over" ]]; then
+            printf "${C_ERROR}%-24s %10s %12s %10s${C_RESET}\n" "$project" "$cpu_sum" "$mem_sum" "$over"
+        else
+            printf "${C_CONTRAST}%-24s${C_RESET} %10s %12s ${C_SUCCESS}%10s${C_RESET}\n" \
+                "$project" "$cpu_sum" "$mem_sum" "ok"
+        fi
+    done < <(cut -f1 "$agg_file" | sort -u)
+
+    rm -f "$agg_file"
+}
+
+# ═══════════════════════════════════════════════════════════════
+# METRIC SHIPPING
+# ═══════════════════════════════════════════════════════════════
+
+# Convert a human size (123.4MiB, 1.2GB) to bytes
+# Args: $1 = size string
+_size_to_bytes() {
+    echo "$1" | awk '
+        /KiB|kB/ { printf "%.0f", $1 * 1024; next }
+        /MiB|MB/ { printf "%.0f", $1 * 1048576; next }
+        /GiB|GB/ { printf "%.0f", $1 * 1073741824; next }
+        /B/      { printf "%.0f", $1; next }
+        { print 0 }' 2>/dev/null
+}
+
+# Ship one sample to an InfluxDB endpoint (line protocol over HTTP)
+# Args: $1 = host:port, $2 = database name
+_push_influx() {
+    local hostport="$1" db="$2"
+    local name cpu mem net block
+    local body=""
+    local now
+    now=$(date +%s)000000000
+
+    while IFS=$'\t' read -r name cpu mem net block; do
+        local cpu_val mem_val net_rx net_tx blk_r blk_w
+        cpu_val="${cpu%\%}"
+        mem_val=$(_size_to_bytes "${mem%% /*}")
+        net_rx=$(_size_to_bytes "${net%% /*}")
+        net_tx=$(_size_to_bytes "${net##*/ }")
+        blk_r=$(_size_to_bytes "${block%% /*}")
+        blk_w=$(_size_to_bytes "${block##*/ }")
+        body+="docker_stats,container=${name} cpu=${cpu_val},mem=${mem_val},net_rx=${net_rx},net_tx=${net_tx},blk_read=${blk_r},blk_write=${blk_w} ${now}"$'\n'
+    done < <(_stats_sample)
+
+    curl -s -X POST "http://${hostport}/write?db=${db}" --data-binary "$body" > /dev/null
+}
+
+# Ship one sample to a Graphite plaintext endpoint
+# Args: $1 = host, $2 = port
+_push_graphite() {
+    local host="$1" port="$2"
+    local name cpu mem net block
+    local now
+    now=$(date +%s)
+
+    while IFS=$'\t' read -r name cpu mem net block; do
+        local cpu_val mem_val
+        cpu_val="${cpu%\%}"
+        mem_val=$(_size_to_bytes "${mem%% /*}")
+        {
+            echo "docker.${name}.cpu ${cpu_val} ${now}"
+            echo "docker.${name}.mem ${mem_val} ${now}"
+            echo "docker.${name}.net_rx $(_size_to_bytes "${net%% /*}") ${now}"
+            echo "docker.${name}.net_tx $(_size_to_bytes "${net##*/ }") ${now}"
+            echo "docker.${name}.blk_read $(_size_to_bytes "${block%% /*}") ${now}"
+            echo "docker.${name}.blk_write $(_size_to_bytes "${block##*/ }") ${now}"
+        } > "/dev/tcp/${host}/${port}" 2>/dev/null
+    done < <(_stats_sample)
+}
+
+# Periodically ship per-container metrics to a time-series backend
+# Args: $1 = sink URL (influxdb://host:port/db or graphite://host:port)
+# Usage: d stats --push influxdb://localhost:8086/docker
+d_stats_push() {
+    local sink="${1:-$(d_conf_get "stats.push" "")}"
+    local interval
+    interval=$(d_conf_get "stats.interval" "10")
+
+    if [[ -z "$sink" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d stats --push <influxdb://host:port/db | graphite://host:port>${C_RESET}" >&2
+        return 1
+    fi
+
+    local scheme="${sink%%://*}"
+    local rest="${sink#*://}"
+
+    echo -e "${C_INFO}ℹ Shipping stats to ${sink} every ${interval}s (Ctrl-C to stop)...${C_RESET}"
+    while true; do
+        case "$scheme" in
+            influxdb)
+                _push_influx "${rest%%/*}" "${rest#*/}"
+                ;;
+            graphite)
+                _push_graphite "${rest%%:*}" "${rest#*:}"
+                ;;
+            *)
+                echo -e "${C_ERROR}✗ Unknown sink scheme: ${scheme}${C_RESET}" >&2
+                return 1
+                ;;
+        esac
+        sleep "$interval"
+    done
+}
+
+# ═══════════════════════════════════════════════════════════════
+# SYSTEM OVERVIEW
+# ═══════════════════════════════════════════════════════════════
+
+# Render a sparkline from space-separated percentages (0-100)
+# Args: $1 = value list
+_sparkline() {
+    local blocks=(▁ ▂ ▃ ▄ ▅ ▆ ▇ █)
+    local out="" v idx
+    for v in $1; do
+        v=${v%%.*}
+        idx=$(( v * 8 / 101 ))
+        [[ $idx -gt 7 ]] && idx=7
+        out+="${blocks[$idx]}"
+    done
+    echo "$out"
+}
+
+# One refreshing screen: containers with CPU/mem sparklines, disk
+# totals, and the most recent daemon events
+# Usage: d top
+d_top() {
+    local hist_dir="${D_CACHE_DIR}/top_hist.$$"
+    local events_log="${D_CACHE_DIR}/top_events.$$"
+    mkdir -p "$hist_dir"
+    : > "$events_log"
+
+    # Background event tail for the "recent events" pane
+    docker_cmd events --format '{{.Type}} {{.Action}} {{.Actor.Attributes.name}}' \
+        >> "$events_log" 2>/dev/null &
+    local events_pid=$!
+    trap 'kill "$events_pid" 2>/dev/null; rm -rf "$hist_dir" "$events_log"; trap - INT TERM; return 0' INT TERM
+
+    while true; do
+        local sample
+        sample=$(_stats_sample)
+
+        printf '\033[H\033[2J'
+        echo -e "${C_PURPLE}d top${C_RESET} ${C_MUTED}— $(date '+%H:%M:%S') — Ctrl-C to quit${C_RESET}"
+        echo ""
+
+        printf "${C_SUBTLE}%-24s %8s %-14s %-20s${C_RESET}\n" "CONTAINER" "CPU" "TREND" "MEM"
+        local name cpu mem net block hist
+        while IFS=$'\t' read -r name cpu mem net block; do
+            [[ -z "$name" ]] && continue
+            hist="${hist_dir}/${name}"
+            echo "${cpu%\%}" >> "$hist"
+            # Keep the last 14 samples per container
+            tail -14 "$hist" > "${hist}.t" && mv "${hist}.t" "$hist"
+            printf "${C_CONTRAST}%-24s${C_RESET} ${C_GLOW}%8s${C_RESET} ${C_INFO}%-14s${C_RESET} ${C_SUBTLE}%-20s${C_RESET}\n" \
+                "${name:0:24}" "$cpu" "$(_sparkline "$(tr '\n' ' ' < "$hist")")" "$mem"
+        done <<< "$sample"
+        [[ -z "$sample" ]] && echo -e "${C_MUTED}No running containers${C_RESET}"
+
+        echo ""
+        docker_cmd system df --format '{{.Type}}\t{{.TotalCount}}\t{{.Size}}\t{{.Reclaimable}}' 2>/dev/null \
+            | while IFS=$'\t' read -r dtype count size reclaim; do
+                printf "${C_MUTED}%-14s %4s items %10s (%s reclaimable)${C_RESET}\n" \
+                    "$dtype" "$count" "$size" "${reclaim%% *}"
+            done
+
+        if [[ -s "$events_log" ]]; then
+            echo ""
+            echo -e "${C_SUBTLE}RECENT EVENTS${C_RESET}"
+            tail -5 "$events_log" | while IFS= read -r line; do
+                echo -e "${C_MUTED}  ${line}${C_RESET}"
+            done
+        fi
+
+        sleep 2
+    done
+}
@@ -0,0 +1,286 @@
+#!/usr/bin/env bash
+#
+# Termflix Docker Swarm Module
+# Stack and service subcommands for `d s ...` with numbered selections
+#
+
+# Prevent multiple sourcing
+[[ -n "${_TERMFLIX_DOCKER_SWARM_LOADED:-}" ]] && return 0
+_TERMFLIX_DOCKER_SWARM_LOADED=1
+
+# Swarm service cache (tab-separated): num, id, name, mode, replicas, image
+D_SWARM_SVC_CACHE="${D_CACHE_DIR}/swarm_services"
+
+# Secret/config caches (tab-separated): num, id, name, created
+D_SECRET_CACHE="${D_CACHE_DIR}/swarm_secrets"
+D_SWARM_CFG_CACHE="${D_CACHE_DIR}/swarm_configs"
+
+# ═══════════════════════════════════════════════════════════════
+# STACKS & SERVICES
+# ═══════════════════════════════════════════════════════════════
+
+# Bail out early with a hint when the daemon isn't a swarm member
+_swarm_active() {
+    local state
+    state=$(docker_cmd info --format '{{.Swarm.LocalNodeState}}' 2>/dev/null)
+    if [[ "$state" != "active" ]]; then
+        echo -e "${C_ERROR}✗ This daemon is not part of a swarm (docker swarm init)${C_RESET}" >&2
+        return 1
+    fi
+}
+
+# List stacks with service counts
+# Usage: d s ls
+swarm_stacks() {
+    _swarm_active || return 1
+    docker_cmd stack ls --format '{{.Name}}\t{{.Services}}' \
+        | while IFS=$'\t' read -r name services; do
+            printf "${C_CONTRAST}%-28s${C_RESET} ${C_MUTED}%s services${C_RESET}\n" \
+                "$name" "$services"
+        done
+}
+
+# Refresh the numbered swarm service cache
+swarm_refresh_services() {
+    _swarm_active || return 1
+    init_docker_cache
+
+    local raw
+    raw=$(docker_capture service ls \
+        --format '{{.ID}}\t{{.Name}}\t{{.Mode}}\t{{.Replicas}}\t{{.Image}}') || return 1
+
+    : > "$D_SWARM_SVC_CACHE"
+    local num=1 line
+    while IFS= read -r line; do
+        d_valid_row "$line" 5 || continue
+        printf '%d\t%s\n' "$num" "$line" >> "$D_SWARM_SVC_CACHE"
+        ((num++))
+    done <<< "$raw"
+}
+
+# Resolve a selection token to a swarm service ID
+# Args: $1 = token (number or service name)
+d_resolve_swarm_service() {
+    local token="$1"
+
+    [[ -f "$D_SWARM_SVC_CACHE" && -s "$D_SWARM_SVC_CACHE" ]] || swarm_refresh_services || return 1
+
+    if [[ "$token" =~ ^[0-9]+$ ]]; then
+        local id
+        id=$(awk -F'\t' -v n="$token" '$1 == n { print $2 }' "$D_SWARM_SVC_CACHE")
+        if [[ -n "$id" ]]; then
+            echo "$id"
+            return 0
+        fi
+        echo -e "${C_ERROR}✗ No service numbered ${token} — run 'd s svc' first${C_RESET}" >&2
+        return 1
+    fi
+    echo "$token"
+}
+
+# Numbered swarm service listing
+# Usage: d s svc
+swarm_services() {
+    if ! swarm_refresh_services; then
+        return 1
+    fi
+
+    if [[ "$D_PORCELAIN" == "1" ]]; then
+        # v1: swarmservice <num> <id> <name> <mode> <replicas> <image>
+        sed 's/^/swarmservice\t/' "$D_SWARM_SVC_CACHE"
+        return 0
+    fi
+
+    if [[ ! -s "$D_SWARM_SVC_CACHE" ]]; then
+        echo -e "${C_MUTED}No swarm services${C_RESET}"
+        return 0
+    fi
+
+    local num id name mode replicas image
+    while IFS=$'\t' read -r num id name mode replicas image; do
+        local color="$C_SUCCESS"
+        [[ "${replicas%%/*}" != "${replicas##*/}" ]] && color="$C_WARNING"
+        printf "${C_GLOW}[%s]${C_RESET} ${C_CONTRAST}%-28s${C_RESET} ${color}%-8s${C_RESET} ${C_SUBTLE}%-12s${C_RESET} ${C_MUTED}%s${C_RESET}\n" \
+            "$num" "${name:0:28}" "$replicas" "$mode" "${image:0:40}"
+    done < "$D_SWARM_SVC_CACHE"
+}
+
+# Scale a numbered service
+# Args: $1 = service token, $2 = replica count
+# Usage: d s scale 2 5
+swarm_scale() {
+    if [[ -z "${1:-}" || ! "${2:-}" =~ ^[0-9]+$ ]]; then
+        echo -e "${C_ERROR}✗ Usage: d s scale <service#> <replicas>${C_RESET}" >&2
+        return 1
+    fi
+
+    local id
+    id=$(d_resolve_swarm_service "$1") || return 1
+    docker_cmd service scale "${id}=${2}" && swarm_refresh_services
+}
+
+# Stream logs for a numbered service, flags passed through
+# Args: $1 = service token, rest = docker service logs flags
+# Usage: d s logs 1 -f
+swarm_logs() {
+    if [[ -z "${1:-}" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d s logs <service#> [-f]${C_RESET}" >&2
+        return 1
+    fi
+
+    local id
+    id=$(d_resolve_swarm_service "$1") || return 1
+    shift
+    docker_cmd service logs "$@" "$id"
+}
+
+# Deploy (or update) a stack from a compose-format file
+# Args: $1 = stack file, $2 = optional stack name (defaults to the
+#       file's directory name)
+# Usage: d s deploy stack.yml [name]
+swarm_deploy() {
+    _swarm_active || return 1
+
+    local file="${1:-}" name="${2:-}"
+    if [[ ! -f "$file" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d s deploy <stack.yml> [name]${C_RESET}" >&2
+        return 1
+    fi
+    [[ -z "$name" ]] && name=$(basename "$(cd "$(dirname "$file")" && pwd)")
+
+    if docker_cmd stack deploy -c "$file" "$name"; then
+        echo -e "${C_SUCCESS}✓ Deployed stack ${name}${C_RESET}"
+        swarm_refresh_services
+    else
+        return 1
+    fi
+}
+
+# ═══════════════════════════════════════════════════════════════
+# SECRETS & CONFIGS
+# ═══════════════════════════════════════════════════════════════
+
+# Secrets and configs share the same shape, so one set of helpers
+# drives both; $1 selects the docker object ("secret" or "config").
+
+_swarm_obj_cache() {
+    [[ "$1" == "secret" ]] && echo "$D_SECRET_CACHE" || echo "$D_SWARM_CFG_CACHE"
+}
+
+# Refresh the numbered cache for secrets or configs
+# Args: $1 = object kind
+_swarm_obj_refresh() {
+    local kind="$1" cache
+    cache=$(_swarm_obj_cache "$kind")
+    _swarm_active || return 1
+    init_docker_cache
+
+    local raw
+    raw=$(docker_capture "$kind" ls \
+        --format '{{.ID}}\t{{.Name}}\t{{.CreatedAt}}') || return 1
+
+    : > "$cache"
+    local num=1 line
+    while IFS= read -r line; do
+        d_valid_row "$line" 3 || continue
+        printf '%d\t%s\n' "$num" "$line" >> "$cache"
+        ((num++))
+    done <<< "$raw"
+}
+
+# Numbered listing of secrets or configs
+# Args: $1 = object kind
+_swarm_obj_ls() {
+    local kind="$1" cache
+    cache=$(_swarm_obj_cache "$kind")
+    _swarm_obj_refresh "$kind" || return 1
+
+    if [[ "$D_PORCELAIN" == "1" ]]; then
+        # v1: <kind> <num> <id> <name> <created>
+        sed "s/^/${kind}\t/" "$cache"
+        return 0
+    fi
+
+    if [[ ! -s "$cache" ]]; then
+        echo -e "${C_MUTED}No ${kind}s${C_RESET}"
+        return 0
+    fi
+
+    local num id name created
+    while IFS=$'\t' read -r num id name created; do
+        printf "${C_GLOW}[%s]${C_RESET} ${C_CONTRAST}%-32s${C_RESET} ${C_MUTED}%s${C_RESET}\n" \
+            "$num" "${name:0:32}" "$created"
+    done < "$cache"
+}
+
+# Create a secret or config from a file, or stdin when piped
+# Args: $1 = object kind, $2 = name, $3 = optional file
+_swarm_obj_create() {
+    local kind="$1" name="${2:-}" file="${3:-}"
+    if [[ -z "$name" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d ${kind} create <name> [file]${C_RESET}" >&2
+        return 1
+    fi
+    _swarm_active || return 1
+
+    if [[ -n "$file" ]]; then
+        if [[ ! -f "$file" ]]; then
+            echo -e "${C_ERROR}✗ File not found: ${file}${C_RESET}" >&2
+            return 1
+        fi
+        docker_cmd "$kind" create "$name" "$file"
+    elif [[ ! -t 0 ]]; then
+        docker_cmd "$kind" create "$name" -
+    else
+        echo -e "${C_ERROR}✗ Provide a file or pipe the value on stdin${C_RESET}" >&2
+        return 1
+    fi
+}
+
+# Remove secrets or configs by number or name
+# Args: $1 = object kind, rest = selection tokens
+_swarm_obj_rm() {
+    local kind="$1" cache
+    cache=$(_swarm_obj_cache "$kind")
+    shift
+
+    if [[ $# -eq 0 ]]; then
+        echo -e "${C_ERROR}✗ Usage: d ${kind} rm <numbers|names>...${C_RESET}" >&2
+        return 1
+    fi
+    [[ -f "$cache" && -s "$cache" ]] || _swarm_obj_refresh "$kind" || return 1
+
+    local token sel id rc=0
+    for token in $(d_expand_selection "$@"); do
+        if [[ "$token" =~ ^[0-9]+$ ]]; then
+            id=$(awk -F'\t' -v n="$token" '$1 == n { print $2 }' "$cache")
+            if [[ -z "$id" ]]; then
+                echo -e "${C_ERROR}✗ No ${kind} numbered ${token} — run 'd ${kind} ls' first${C_RESET}" >&2
+                rc=1
+                continue
+            fi
+        else
+            id="$token"
+        fi
+        docker_cmd "$kind" rm "$id" || rc=1
+    done
+    _swarm_obj_refresh "$kind"
+    return $rc
+}
+
+# Dispatch `d secret ...` / `d config ...`
+# Args: $1 = object kind, $2 = subcommand, rest = args
+swarm_object_dispatch() {
+    local kind="$1" sub="${2:-ls}"
+    shift; shift 2>/dev/null || true
+
+    case "$sub" in
+        ls) _swarm_obj_ls "$kind" ;;
+        create) _swarm_obj_create "$kind" "$@" ;;
+        rm) _swarm_obj_rm "$kind" "$@" ;;
+        *)
+            echo -e "${C_ERROR}✗ Unknown ${kind} command: ${sub}${C_RESET}" >&2
+            return 1
+            ;;
+    esac
+}
@@ -0,0 +1,344 @@
+#!/usr/bin/env bash
+#
+# Termflix Docker System Module
+# Daemon-wide commands: prune, disk usage, diagnostics
+#
+
+# Prevent multiple sourcing
+[[ -n "${_TERMFLIX_DOCKER_SYSTEM_LOADED:-}" ]] && return 0
+_TERMFLIX_DOCKER_SYSTEM_LOADED=1
+
+# ═══════════════════════════════════════════════════════════════
+# DOCKER CONTEXTS
+# ═══════════════════════════════════════════════════════════════
+
+# Context cache file (same numbered format as the other listings)
+D_CONTEXT_CACHE="${D_CACHE_DIR}/contexts"
+
+# List docker contexts with numbers
+d_ctx_ls() {
+    init_docker_cache
+
+    local num=1
+    local line
+    : > "$D_CONTEXT_CACHE"
+
+    while IFS= read -r line; do
+        [[ -z "$line" ]] && continue
+        printf '%d\t%s\n' "$num" "$line" >> "$D_CONTEXT_CACHE"
+        ((num++))
+    done < <("$DOCKER_BIN" context ls --format '{{.Name}}\t{{.DockerEndpoint}}\t{{if .Current}}*{{end}}')
+
+    local name endpoint current
+    while IFS=$'\t' read -r num name endpoint current; do
+        if [[ "$current" == "*" ]]; then
+            printf "${C_GLOW}[%s]${C_RESET} ${C_SUCCESS}%-20s${C_RESET} ${C_SUBTLE}%s${C_RESET} ${C_SUCCESS}(current)${C_RESET}\n" \
+                "$num" "$name" "$endpoint"
+        else
+            printf "${C_GLOW}[%s]${C_RESET} ${C_CONTRAST}%-20s${C_RESET} ${C_SUBTLE}%s${C_RESET}\n" \
+                "$num" "$name" "$endpoint"
+        fi
+    done < "$D_CONTEXT_CACHE"
+}
+
+# Switch the default docker context by listing number
+# Args: $1 = context number from `d ctx`
+d_ctx_use() {
+    local token="$1"
+    [[ -f "$D_CONTEXT_CACHE" ]] || d_ctx_ls > /dev/null
+
+    local name
+    if [[ "$token" =~ ^[0-9]+$ ]]; then
+        name=$(awk -F'\t' -v n="$token" '$1 == n { print $2 }' "$D_CONTEXT_CACHE")
+    else
+        name="$token"
+    fi
+
+    if [[ -z "$name" ]]; then
+        echo -e "${C_ERROR}✗ No context matches '${token}' — run 'd ctx' first${C_RESET}" >&2
+        return 1
+    fi
+
+    if "$DOCKER_BIN" context use "$name" > /dev/null; then
+        echo -e "${C_SUCCESS}✓ Switched to context ${name}${C_RESET}"
+    else
+        echo -e "${C_ERROR}✗ Could not switch to ${name}${C_RESET}" >&2
+        return 1
+    fi
+}
+
+# ═══════════════════════════════════════════════════════════════
+# GRANULAR PRUNE
+# ═══════════════════════════════════════════════════════════════
+
+# Preview what a prune would remove for one category
+# Args: $1 = category
+_prune_preview() {
+    case "$1" in
+        containers)
+            docker_cmd ps -a --filter status=exited --filter status=created \
+                --format '  {{.Names}} ({{.Status}})'
+            ;;
+        images)
+            docker_cmd images --filter dangling=true --format '  {{.ID}} {{.Size}}'
+            ;;
+        volumes)
+            docker_cmd volume ls -q --filter dangling=true | sed 's/^/  /'
+            ;;
+        networks)
+            echo -e "  ${C_MUTED}(unused networks are determined by the daemon at prune time)${C_RESET}"
+            ;;
+        builder)
+            docker_cmd system df --format '{{.Type}}\t{{.Reclaimable}}' 2>/dev/null \
+                | awk -F'\t' '$1 == "Build Cache" { print "  reclaimable: " $2 }'
+            ;;
+    esac
+}
+
+# Prune one resource category at a time
+# Args: $1 = containers|images|volumes|networks|builder, optionally
+#       --dry-run and --older-than <dur> (e.g. 720h; everything the
+#       daemon accepts for an until= filter)
+# Usage: d prune images [--older-than 720h] [--dry-run]
+system_prune() {
+    local category="" dry_run=false older_than=""
+
+    while [[ $# -gt 0 ]]; do
+        case "$1" in
+            --dry-run) dry_run=true; shift ;;
+            --older-than)
+                [[ $# -ge 2 ]] || { echo -e "${C_ERROR}✗ --older-than needs a duration${C_RESET}" >&2; return 1; }
+                older_than="$2"; shift 2 ;;
+            --older-than=*) older_than="${1#*=}"; shift ;;
+            *) category="$1"; shift ;;
+        esac
+    done
+    [[ "$D_DRY_RUN" == "1" ]] && dry_run=true
+
+    case "$category" in
+        containers|images|volumes|networks|builder) ;;
+        *)
+            echo -e "${C_ERROR}✗ Usage: d prune containers|images|volumes|networks|builder [--older-than DUR] [--dry-run]${C_RESET}" >&2
+            return 1
+            ;;
+    esac
+
+    # The daemon has no until= filter for volumes
+    if [[ -n "$older_than" && "$category" == "volumes" ]]; then
+        echo -e "${C_ERROR}✗ --older-than is not supported for volumes${C_RESET}" >&2
+        return 1
+    fi
+    local age_filter=()
+    [[ -n "$older_than" ]] && age_filter=(--filter "until=${older_than}")
+
+    if [[ "$dry_run" == true ]]; then
+        echo -e "${C_INFO}ℹ Would prune (${category}):${C_RESET}"
+        local preview
+        preview=$(_prune_preview "$category")
+        if [[ -n "$preview" ]]; then
+            echo "$preview"
+        else
+            echo -e "  ${C_MUTED}nothing to prune${C_RESET}"
+        fi
+        return 0
+    fi
+
+    local output rc=0
+    case "$category" in
+        containers) output=$(docker_cmd container prune -f "${age_filter[@]+"${age_filter[@]}"}" 2>&1) || rc=$? ;;
+        images)     output=$(docker_cmd image prune -f "${age_filter[@]+"${age_filter[@]}"}" 2>&1) || rc=$? ;;
+        volumes)    output=$(docker_cmd volume prune -f 2>&1) || rc=$? ;;
+        networks)   output=$(docker_cmd network prune -f "${age_filter[@]+"${age_filter[@]}"}" 2>&1) || rc=$? ;;
+        builder)    output=$(docker_cmd builder prune -f "${age_filter[@]+"${age_filter[@]}"}" 2>&1) || rc=$? ;;
+    esac
+
+    if [[ $rc -ne 0 ]]; then
+        echo -e "${C_ERROR}✗ Prune failed:${C_RESET}" >&2
+        echo "$output" | sed 's/^/    /' >&2
+        return $rc
+    fi
+
+    local reclaimed
+    reclaimed=$(echo "$output" | grep -i 'reclaimed space' | sed 's/.*: //')
+    echo -e "${C_SUCCESS}✓ Pruned ${category}${reclaimed:+ — reclaimed ${reclaimed}}${C_RESET}"
+}
+
+# ═══════════════════════════════════════════════════════════════
+# ENGINE PLUGINS
+# ═══════════════════════════════════════════════════════════════
+
+# Plugin cache (tab-separated): num, id, name, enabled, description
+D_PLUGIN_CACHE="${D_CACHE_DIR}/plugins"
+
+# Refresh the numbered engine plugin cache
+d_plugin_refresh() {
+    init_docker_cache
+
+    local raw
+    raw=$(docker_capture plugin ls \
+        --format '{{.ID}}\t{{.Name}}\t{{.Enabled}}\t{{.Description}}') || return 1
+
+    : > "$D_PLUGIN_CACHE"
+    local num=1 line
+    while IFS= read -r line; do
+        d_valid_row "$line" 4 || continue
+        printf '%d\t%s\n' "$num" "$line" >> "$D_PLUGIN_CACHE"
+        ((num++))
+    done <<< "$raw"
+}
+
+# List engine plugins (volume/network drivers) with numbers
+# Usage: d plugin ls
+d_plugin_ls() {
+    d_plugin_refresh || return 1
+
+    if [[ "$D_PORCELAIN" == "1" ]]; then
+        # v1: plugin <num> <id> <name> <enabled> <description>
+        sed 's/^/plugin\t/' "$D_PLUGIN_CACHE"
+        return 0
+    fi
+
+    if [[ ! -s "$D_PLUGIN_CACHE" ]]; then
+        echo -e "${C_MUTED}No engine plugins installed${C_RESET}"
+        return 0
+    fi
+
+    local num id name enabled desc
+    while IFS=$'\t' read -r num id name enabled desc; do
+        local state="${C_MUTED}disabled${C_RESET}"
+        [[ "$enabled" == "true" ]] && state="${C_SUCCESS}enabled${C_RESET} "
+        printf "${C_GLOW}[%s]${C_RESET} ${C_CONTRAST}%-36s${C_RESET} %b ${C_SUBTLE}%s${C_RESET}\n" \
+            "$num" "${name:0:36}" "$state" "${desc:0:40}"
+    done < "$D_PLUGIN_CACHE"
+}
+
+# Run a plugin verb against numbered or named plugins
+# Args: $1 = verb (enable|disable|rm), rest = selection tokens
+d_plugin_op() {
+    local verb="$1"
+    shift
+
+    if [[ $# -eq 0 ]]; then
+        echo -e "${C_ERROR}✗ Usage: d plugin ${verb} <numbers|names>...${C_RESET}" >&2
+        return 1
+    fi
+    [[ -f "$D_PLUGIN_CACHE" && -s "$D_PLUGIN_CACHE" ]] || d_plugin_refresh || return 1
+
+    local token name rc=0
+    for token in $(d_expand_selection "$@"); do
+        if [[ "$token" =~ ^[0-9]+$ ]]; then
+            name=$(awk -F'\t' -v n="$token" '$1 == n { print $3 }' "$D_PLUGIN_CACHE")
+            if [[ -z "$name" ]]; then
+                echo -e "${C_ERROR}✗ No plugin numbered ${token} — run 'd plugin ls' first${C_RESET}" >&2
+                rc=1
+                continue
+            fi
+        else
+            name="$token"
+        fi
+        docker_cmd plugin "$verb" "$name" || rc=1
+    done
+    d_plugin_refresh
+    return $rc
+}
+
+# ═══════════════════════════════════════════════════════════════
+# DOCTOR
+# ═══════════════════════════════════════════════════════════════
+
+# One pass/fail/warn line per check
+# Args: $1 = status (ok|warn|fail), $2 = label, $3 = detail
+_doctor_line() {
+    case "$1" in
+        ok)   printf "${C_SUCCESS}✓${C_RESET} %-22s ${C_MUTED}%s${C_RESET}\n" "$2" "$3" ;;
+        warn) printf "${C_WARNING}⚠${C_RESET} %-22s ${C_WARNING}%s${C_RESET}\n" "$2" "$3" ;;
+        fail) printf "${C_ERROR}✗${C_RESET} %-22s ${C_ERROR}%s${C_RESET}\n" "$2" "$3" ;;
+    esac
+}
+
+# Environment diagnostics for onboarding and broken setups
+# Usage: d doctor
+d_doctor() {
+    local rc=0
+
+    # Binary
+    if command -v "$DOCKER_BIN" &> /dev/null; then
+        _doctor_line ok "docker binary" "$(command -v "$DOCKER_BIN")"
+    else
+        _doctor_line fail "docker binary" "${DOCKER_BIN} not on PATH — install docker, podman, or nerdctl"
+        rc=1
+    fi
+
+    # Daemon
+    local version
+    version=$("$DOCKER_BIN" version --format '{{.Server.Version}}' 2>/dev/null)
+    if [[ -n "$version" ]]; then
+        _doctor_line ok "daemon" "reachable, server ${version}"
+    else
+        _doctor_line fail "daemon" "not reachable — is the daemon running? (check DOCKER_HOST)"
+        rc=1
+    fi
+
+    # Compose
+    if "$DOCKER_BIN" compose version &> /dev/null; then
+        _doctor_line ok "compose" "v2 plugin ($("$DOCKER_BIN" compose version --short 2>/dev/null))"
+    elif command -v docker-compose &> /dev/null; then
+        _doctor_line warn "compose" "only legacy v1 docker-compose found — consider the v2 plugin"
+    else
+        _doctor_line warn "compose" "not installed — dc commands will not work"
+    fi
+
+    # Context
+    local ctx endpoint
+    ctx=$("$DOCKER_BIN" context show 2>/dev/null)
+    if [[ -n "$ctx" ]]; then
+        endpoint=$("$DOCKER_BIN" context inspect "$ctx" --format '{{.Endpoints.docker.Host}}' 2>/dev/null)
+        if [[ "$endpoint" == unix://* && ! -S "${endpoint#unix://}" ]]; then
+            _doctor_line warn "context" "${ctx} points at missing socket ${endpoint#unix://}"
+        else
+            _doctor_line ok "context" "${ctx} (${endpoint:-unknown endpoint})"
+        fi
+    else
+        _doctor_line warn "context" "no current context"
+    fi
+
+    # Disk pressure on the data root
+    local data_root usage
+    data_root=$("$DOCKER_BIN" info --format '{{.DockerRootDir}}' 2>/dev/null)
+    if [[ -n "$data_root" ]]; then
+        usage=$(df -P "$data_root" 2>/dev/null | awk 'NR == 2 { sub(/%/, "", $5); print $5 }')
+        if [[ -z "$usage" ]]; then
+            _doctor_line warn "disk" "could not stat ${data_root}"
+        elif [[ "$usage" -ge 90 ]]; then
+            _doctor_line fail "disk" "${data_root} at ${usage}% — run 'd prune' soon"
+            rc=1
+        elif [[ "$usage" -ge 75 ]]; then
+            _doctor_line warn "disk" "${data_root} at ${usage}%"
+        else
+            _doctor_line ok "disk" "${data_root} at ${usage}%"
+        fi
+    fi
+
+    # Credential helpers referenced from the docker config
+    local helper
+    helper=$(jq -r '.credsStore // empty' "${DOCKER_CONFIG:-$HOME/.docker}/config.json" 2>/dev/null)
+    if [[ -n "$helper" ]]; then
+        if command -v "docker-credential-${helper}" &> /dev/null; then
+            _doctor_line ok "credentials" "docker-credential-${helper}"
+        else
+            _doctor_line fail "credentials" "config wants docker-credential-${helper} but it is not on PATH"
+            rc=1
+        fi
+    else
+        _doctor_line warn "credentials" "no credsStore — registry passwords stored base64 in config.json"
+    fi
+
+    # Rootless
+    if [[ "$("$DOCKER_BIN" info --format '{{.SecurityOptions}}' 2>/dev/null)" == *rootless* ]]; then
+        _doctor_line ok "rootless" "daemon runs rootless"
+    else
+        _doctor_line ok "rootless" "daemon runs as root (the default)"
+    fi
+
+    return $rc
+}
@@ -0,0 +1,395 @@
+#!/usr/bin/env bash
+#
+# Termflix Docker Volumes Module
+# Volume subcommands for `d v ...` operating on numbered selections
+#
+
+# Prevent multiple sourcing
+[[ -n "${_TERMFLIX_DOCKER_VOLUMES_LOADED:-}" ]] && return 0
+_TERMFLIX_DOCKER_VOLUMES_LOADED=1
+
+# Volume cache file
+D_VOLUME_CACHE="${D_CACHE_DIR}/volumes"
+
+# ═══════════════════════════════════════════════════════════════
+# CACHE & LISTING
+# ═══════════════════════════════════════════════════════════════
+
+# Refresh the volume cache from docker volume ls
+# Cache format (tab-separated): num, name, driver
+d_refresh_volumes() {
+    init_docker_cache
+
+    local raw rc=0
+    raw=$(docker_capture volume ls --format '{{.Name}}\t{{.Driver}}') || rc=$?
+
+    : > "$D_VOLUME_CACHE"
+    [[ $rc -ne 0 ]] && return 1
+
+    local num=1
+    local line
+    while IFS= read -r line; do
+        d_valid_row "$line" 2 || continue
+        printf '%d\t%s\n' "$num" "$line" >> "$D_VOLUME_CACHE"
+        ((num++))
+    done <<< "$raw"
+}
+
+# Resolve a selection token (listing number) to a volume name
+# Args: $1 = token from the command line
+d_resolve_volume() {
+    local token="$1"
+
+    [[ -f "$D_VOLUME_CACHE" ]] || d_refresh_volumes
+
+    if [[ "$token" =~ ^[0-9]+$ ]]; then
+        local name
+        name=$(awk -F'\t' -v n="$token" '$1 == n { print $2 }' "$D_VOLUME_CACHE")
+        if [[ -n "$name" ]]; then
+            echo "$name"
+            return 0
+        fi
+    fi
+
+    echo -e "${C_ERROR}✗ No volume matches '${token}' — run 'd v ls' to see the numbered list${C_RESET}" >&2
+    return 1
+}
+
+# Print the numbered volume listing with on-disk sizes
+# Args: optionally --sort size to order by size (largest first)
+d_list_volumes() {
+    local sort_by=""
+    while [[ $# -gt 0 ]]; do
+        case "$1" in
+            --sort)   sort_by="$2"; shift ;;
+            --sort=*) sort_by="${1#*=}" ;;
+        esac
+        shift
+    done
+
+    if ! d_refresh_volumes; then
+        d_listing_error "volumes"
+        return 1
+    fi
+
+    if [[ "$D_PORCELAIN" == "1" ]]; then
+        # v1: volume <num> <name> <driver>
+        sed 's/^/volume\t/' "$D_VOLUME_CACHE"
+        return 0
+    fi
+
+    if [[ ! -s "$D_VOLUME_CACHE" ]]; then
+        echo -e "${C_MUTED}No volumes found${C_RESET}"
+        return 0
+    fi
+
+    # One system df call covers every volume's size
+    local sizes
+    sizes=$(docker_cmd system df -v --format '{{range .Volumes}}{{.Name}}\t{{.Size}}\n{{end}}' 2>/dev/null)
+
+    # Rows annotated with size (and sort key when requested)
+    local rows="" num name driver
+    while IFS=$'\t' read -r num name driver; do
+        local size bytes
+        size=$(echo "$sizes" | awk -F'\t' -v v="$name" '$1 == v { print $2 }')
+        bytes=$(_size_to_bytes "${size:-0}")
+        rows+="${bytes}"$'\t'"${num}"$'\t'"${name}"$'\t'"${driver}"$'\t'"${size:--}"$'\n'
+    done < "$D_VOLUME_CACHE"
+
+    if [[ "$sort_by" == "size" ]]; then
+        rows=$(echo -n "$rows" | sort -t$'\t' -k1,1nr)
+    fi
+
+    local _bytes size
+    while IFS=$'\t' read -r _bytes num name driver size; do
+        [[ -z "$num" ]] && continue
+        printf "${C_GLOW}[%s]${C_RESET} ${C_CONTRAST}%-40s${C_RESET} ${C_SUBTLE}%-10s${C_RESET} ${C_MUTED}%s${C_RESET}\n" \
+            "$num" "${name:0:40}" "$size" "$driver"
+    done <<< "$rows"
+}
+
+# Readable summary of docker inspect for one volume
+# Args: $1 = selection token, optionally --raw for the plain JSON
+# Usage: d v inspect 2 [--raw]
+volume_inspect() {
+    local raw=false token=""
+    local arg
+    for arg in "$@"; do
+        case "$arg" in
+            --raw) raw=true ;;
+            *) token="$arg" ;;
+        esac
+    done
+
+    if [[ -z "$token" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d v inspect <number> [--raw]${C_RESET}" >&2
+        return 1
+    fi
+
+    local name
+    name=$(d_resolve_volume "$token") || return 1
+
+    local json
+    json=$(docker_capture volume inspect "$name") || {
+        echo -e "${C_ERROR}✗ Could not inspect volume${C_RESET}" >&2
+        return 1
+    }
+
+    if [[ "$raw" == true ]]; then
+        echo "$json"
+        return 0
+    fi
+
+    echo -e "${C_CONTRAST}${name}${C_RESET}"
+    _inspect_line "Driver"     "$(echo "$json" | jq -r '.[0].Driver')"
+    _inspect_line "Mountpoint" "$(echo "$json" | jq -r '.[0].Mountpoint')"
+    _inspect_line "Created"    "$(echo "$json" | jq -r '.[0].CreatedAt // empty')"
+    _inspect_line "Scope"      "$(echo "$json" | jq -r '.[0].Scope // empty')"
+    _inspect_line "Labels"     "$(echo "$json" | jq -r '.[0].Labels // {} | to_entries | map("\(.key)=\(.value)") | join(", ")')"
+
+    # Cross-reference which containers mount this volume, and where
+    local users
+    users=$(docker_cmd ps -a --filter "volume=${name}" --format '{{.ID}}\t{{.Names}}')
+    if [[ -n "$users" ]]; then
+        echo -e "  ${C_SUBTLE}Mounted by${C_RESET}"
+        local cid cname
+        while IFS=$'\t' read -r cid cname; do
+            local dest
+            dest=$(docker_cmd inspect "$cid" 2>/dev/null \
+                | jq -r --arg v "$name" '.[0].Mounts[] | select(.Name == $v) | .Destination + (if .RW then "" else " (ro)" end)')
+            echo -e "    ${cname} ${C_MUTED}→ ${dest}${C_RESET}"
+        done <<< "$users"
+    else
+        echo -e "  ${C_MUTED}Not mounted by any container${C_RESET}"
+    fi
+}
+
+# Create a volume via flags or interactive prompts, then re-list
+# Args: optionally --name, --driver, -o key=val (repeatable), --label k=v (repeatable)
+# Usage: d v create / d v create --name data --driver local
+volume_create() {
+    local name="" driver=""
+    local opts=() labels=()
+
+    while [[ $# -gt 0 ]]; do
+        case "$1" in
+            --name)    name="$2"; shift ;;
+            --driver)  driver="$2"; shift ;;
+            -o|--opt)  opts+=(--opt "$2"); shift ;;
+            --label)   labels+=(--label "$2"); shift ;;
+            *)         name="$1" ;;
+        esac
+        shift
+    done
+
+    # Interactive when nothing was given on the command line
+    if [[ -z "$name" ]]; then
+        name=$(_prompt_default "Volume name" "")
+        if [[ -z "$name" ]]; then
+            echo -e "${C_ERROR}✗ A volume needs a name${C_RESET}" >&2
+            return 1
+        fi
+        driver=$(_prompt_default "Driver" "local")
+        local value
+        while true; do
+            echo -en "${C_INFO}Driver option key=val (empty to finish): ${C_RESET}" >&2
+            read -r value
+            [[ -z "$value" ]] && break
+            opts+=(--opt "$value")
+        done
+        while true; do
+            echo -en "${C_INFO}Label key=val (empty to finish): ${C_RESET}" >&2
+            read -r value
+            [[ -z "$value" ]] && break
+            labels+=(--label "$value")
+        done
+    fi
+
+    local create_args=()
+    [[ -n "$driver" ]] && create_args+=(--driver "$driver")
+    create_args+=("${opts[@]+"${opts[@]}"}" "${labels[@]+"${labels[@]}"}" "$name")
+
+    if docker_cmd volume create "${create_args[@]}" > /dev/null; then
+        echo -e "${C_SUCCESS}✓ Created volume ${name}${C_RESET}"
+        echo ""
+        d_list_volumes
+    else
+        echo -e "${C_ERROR}✗ Could not create volume ${name}${C_RESET}" >&2
+        return 1
+    fi
+}
+
+# ═══════════════════════════════════════════════════════════════
+# BACKUP / RESTORE
+# ═══════════════════════════════════════════════════════════════
+
+# Archive one volume's contents through a helper container
+# Args: $1 = selection token, -o <file> (.gz compresses; defaults to <name>.tar.gz)
+# Usage: d v backup 2 -o data.tar.gz
+volume_backup() {
+    local out="" token=""
+    while [[ $# -gt 0 ]]; do
+        case "$1" in
+            -o|--output) out="$2"; shift ;;
+            *)           token="$1" ;;
+        esac
+        shift
+    done
+
+    if [[ -z "$token" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d v backup <number> [-o file.tar.gz]${C_RESET}" >&2
+        return 1
+    fi
+
+    local name
+    name=$(d_resolve_volume "$token") || return 1
+    [[ -z "$out" ]] && out="${name}.tar.gz"
+
+    local tar_path="$out"
+    [[ "$out" == *.gz ]] && tar_path="${out%.gz}"
+
+    if ! _backup_volume "$name" "$tar_path"; then
+        echo -e "${C_ERROR}✗ Backup failed for ${name}${C_RESET}" >&2
+        return 1
+    fi
+    [[ "$out" == *.gz ]] && gzip -f "$tar_path"
+
+    echo -e "${C_SUCCESS}✓ Backed up ${name} → ${out} ($(du -h "$out" 2>/dev/null | awk '{ print $1 }'))${C_RESET}"
+}
+
+# Restore an archive into a volume (created if missing)
+# Args: $1 = archive, $2 = volume name
+# Usage: d v restore data.tar.gz myvolume
+volume_restore() {
+    local archive="${1:-}" name="${2:-}"
+
+    if [[ -z "$archive" || -z "$name" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d v restore <archive> <volume>${C_RESET}" >&2
+        return 1
+    fi
+    if [[ ! -f "$archive" ]]; then
+        echo -e "${C_ERROR}✗ No such file: ${archive}${C_RESET}" >&2
+        return 1
+    fi
+
+    # Helper container untars plain archives; decompress .gz first
+    local tar_path="$archive" cleanup=false
+    if [[ "$archive" == *.gz ]]; then
+        tar_path="${archive%.gz}"
+        gunzip -kf "$archive"
+        cleanup=true
+    fi
+
+    local rc=0
+    if _restore_volume "$name" "$tar_path"; then
+        echo -e "${C_SUCCESS}✓ Restored ${archive} → ${name}${C_RESET}"
+        d_refresh_volumes
+    else
+        echo -e "${C_ERROR}✗ Restore failed for ${name}${C_RESET}" >&2
+        rc=1
+    fi
+    [[ "$cleanup" == true ]] && rm -f "$tar_path"
+    return $rc
+}
+
+# Inspect a volume's contents interactively by mounting it into a
+# transient busybox container: a shell on a TTY, a file listing when
+# piped
+# Args: $1 = selection token
+# Usage: d v browse 1
+volume_browse() {
+    if [[ -z "${1:-}" ]]; then
+        echo -e "${C_ERROR}✗ Usage: d v browse <number>${C_RESET}" >&2
+        return 1
+    fi
+
+    local name
+    name=$(d_resolve_volume "$1") || return 1
+
+    if [[ -t 0 && -t 1 ]]; then
+        echo -e "${C_INFO}ℹ Volume mounted read-only at /data — exit the shell to leave${C_RESET}"
+        docker_cmd run --rm -it -v "${name}":/data:ro -w /data busybox sh
+    else
+        docker_cmd run --rm -v "${name}":/data:ro busybox find /data -maxdepth 3
+    fi
+}
+
+# Unused volumes get their own numbered cache, like dangling images
+D_UNUSED_VOLUME_CACHE="${D_CACHE_DIR}/volumes_unused"
+
+# List volumes not referenced by any container, with sizes where the
+# daemon reports them; `rm <numbers|all>` deletes selectively
+# Args: optionally rm plus selection tokens or "all"
+# Usage: d v unused / d v unused rm 1-4
+volume_unused() {
+    init_docker_cache
+
+    local dangling
+    dangling=$(docker_capture volume ls -q --filter dangling=true) || {
+        d_listing_error "volumes"
+        return 1
+    }
+
+    # Sizes come from system df when available (one call, not one
+    # helper container per volume)
+    local sizes
+    sizes=$(docker_cmd system df -v --format '{{range .Volumes}}{{.Name}}\t{{.Size}}\n{{end}}' 2>/dev/null)
+
+    : > "$D_UNUSED_VOLUME_CACHE"
+    local num=1 name
+    while IFS= read -r name; do
+        [[ -z "$name" ]] && continue
+        local size
+        size=$(echo "$sizes" | awk -F'\t' -v v="$name" '$1 == v { print $2 }')
+        printf '%d\t%s\t%s\n' "$num" "$name" "${size:--}" >> "$D_UNUSED_VOLUME_CACHE"
+        ((num++))
+    done <<< "$dangling"
+
+    if [[ "${1:-}" == "rm" ]]; then
+        shift
+        local tokens=("$@")
+        if [[ "${1:-}" == "all" ]]; then
+            tokens=()
+            local n
+            while IFS=$'\t' read -r n _; do
+                tokens+=("$n")
+            done < "$D_UNUSED_VOLUME_CACHE"
+        fi
+        if [[ ${#tokens[@]} -eq 0 ]]; then
+            echo -e "${C_MUTED}Nothing to remove${C_RESET}"
+            return 0
+        fi
+
+        local token rc=0
+        while IFS= read -r token; do
+            name=$(awk -F'\t' -v n="$token" '$1 == n { print $2 }' "$D_UNUSED_VOLUME_CACHE")
+            if [[ -z "$name" ]]; then
+                echo -e "${C_ERROR}✗ No unused volume numbered ${token}${C_RESET}" >&2
+                rc=1
+                continue
+            fi
+            if docker_cmd volume rm "$name" > /dev/null; then
+                echo -e "${C_SUCCESS}✓ Removed volume ${name}${C_RESET}"
+            else
+                echo -e "${C_ERROR}✗ Could not remove ${name}${C_RESET}" >&2
+                rc=1
+            fi
+        done < <(d_expand_selection "${tokens[@]}")
+
+        d_refresh_volumes
+        return $rc
+    fi
+
+    if [[ ! -s "$D_UNUSED_VOLUME_CACHE" ]]; then
+        echo -e "${C_MUTED}No unused volumes${C_RESET}"
+        return 0
+    fi
+
+    local size
+    while IFS=$'\t' read -r num name size; do
+        printf "${C_GLOW}[%s]${C_RESET} ${C_CONTRAST}%-40s${C_RESET} ${C_MUTED}%s${C_RESET}\n" \
+            "$num" "${name:0:40}" "$size"
+    done < "$D_UNUSED_VOLUME_CACHE"
+    echo ""
+    echo -e "${C_INFO}ℹ Remove with 'd v unused rm <numbers|all>'${C_RESET}"
+}